@@ -0,0 +1,203 @@
+// Command mdfmt formats markdown documents.
+//
+// Without arguments it formats standard input to standard output. Given files or
+// directories, it formats every .md and .markdown file found. Headings are written
+// as ATX headings, lists with - bullets and . delimiters, code with backtick fences
+// and pipe tables with aligned columns. Formatting formatted document changes nothing.
+//
+// Usage:
+//
+//	mdfmt [flags] [path ...]
+//
+// The flags are:
+//
+//	-l
+//		list files whose formatting differs from mdfmt's
+//	-w
+//		write result to source file instead of standard output
+//	-d
+//		display diffs instead of rewriting files
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	parser "github.com/chonla/markdown-parser"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose formatting differs from mdfmt's")
+	write = flag.Bool("w", false, "write result to source file instead of stdout")
+	diff  = flag.Bool("d", false, "display diffs instead of rewriting files")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: mdfmt [flags] [path ...]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "error: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		if err := processFile("<standard input>", os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	exitCode := 0
+	for _, path := range flag.Args() {
+		if err := processPath(path, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 2
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// processPath formats file at path, or every markdown file under directory at path
+func processPath(path string, out io.Writer) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return processFile(path, nil, out)
+	}
+
+	return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isMarkdownFile(info.Name()) {
+			return nil
+		}
+		return processFile(path, nil, out)
+	})
+}
+
+// isMarkdownFile checks whether file name has markdown extension
+func isMarkdownFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return !strings.HasPrefix(name, ".") && (ext == ".md" || ext == ".markdown")
+}
+
+// processFile formats file read from in, or from filename when in is nil,
+// and reports result to out following -l, -w and -d flags
+func processFile(filename string, in io.Reader, out io.Writer) error {
+	if in == nil {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	res, err := format(src)
+	if err != nil {
+		return err
+	}
+
+	if !*list && !*write && !*diff {
+		_, err = out.Write(res)
+		return err
+	}
+
+	if bytes.Equal(src, res) {
+		return nil
+	}
+
+	if *list {
+		fmt.Fprintln(out, filename)
+	}
+	if *write {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if *diff {
+		d, err := diffLines(src, res, filename)
+		if err != nil {
+			return fmt.Errorf("computing diff: %s", err)
+		}
+		fmt.Fprintf(out, "diff -u %s.orig %s\n", filename, filename)
+		out.Write(d)
+	}
+
+	return nil
+}
+
+// format parses markdown source and renders it back normalized
+func format(src []byte) ([]byte, error) {
+	doc := parser.Parse(string(src))
+
+	var out bytes.Buffer
+	if err := parser.RenderMarkdown(doc, &out, parser.MarkdownNormalize()); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// diffLines returns unified diff of original and formatted content using diff command
+func diffLines(original, formatted []byte, filename string) ([]byte, error) {
+	f1, err := writeTempFile("mdfmt", original)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f1)
+
+	f2, err := writeTempFile("mdfmt", formatted)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f2)
+
+	data, err := exec.Command("diff", "-u", "--label", filename+".orig", "--label", filename, f1, f2).CombinedOutput()
+	if len(data) > 0 {
+		// diff exits with status 1 when files differ
+		return data, nil
+	}
+	return data, err
+}
+
+// writeTempFile writes data to new temporary file and returns its name
+func writeTempFile(prefix string, data []byte) (string, error) {
+	f, err := ioutil.TempFile("", prefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	src := "Title\n=====\n\n* a\n* b\n\n~~~\ncode\n~~~\n\n|a|b|\n|-|:-:|\n|long|x|\n"
+
+	result, err := format([]byte(src))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n\n- a\n- b\n\n```\ncode\n```\n\n| a    |  b  |\n| ---- | :-: |\n| long |  x  |\n", string(result))
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "testdata", "html", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+
+		once, err := format(src)
+		assert.NoError(t, err)
+		twice, err := format(once)
+		assert.NoError(t, err)
+
+		assert.Equal(t, string(once), string(twice), file)
+	}
+}
+
+func TestProcessFileListAndWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mdfmt")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	formatted := filepath.Join(dir, "formatted.md")
+	unformatted := filepath.Join(dir, "unformatted.md")
+	assert.NoError(t, ioutil.WriteFile(formatted, []byte("# Title\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(unformatted, []byte("Title\n===\n"), 0644))
+
+	*list, *write = true, true
+	defer func() { *list, *write = false, false }()
+
+	var out bytes.Buffer
+	assert.NoError(t, processPath(dir, &out))
+
+	assert.Equal(t, []string{unformatted}, strings.Fields(out.String()))
+	content, err := ioutil.ReadFile(unformatted)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n", string(content))
+}
+
+func TestFormatKeepsRawHTML(t *testing.T) {
+	for _, src := range []string{
+		"<pre>\n+ a\n* b\n</pre>\n",
+		"<!-- comment\n1) a\n-->\n",
+		"<!-- unclosed\ntext\n",
+	} {
+		result, err := format([]byte(src))
+
+		assert.NoError(t, err)
+		assert.Equal(t, src, string(result))
+	}
+}
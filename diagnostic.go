@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Diagnostic is a warning found while parsing markdown document
+type Diagnostic struct {
+	Line    int
+	Message string
+}
+
+// String formats diagnostic with its line number
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+}
+
+// sortDiagnostics orders diagnostics by line, keeping order of diagnostics on the same line
+func sortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+}
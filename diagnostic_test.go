@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticString(t *testing.T) {
+	diagnostic := Diagnostic{
+		Line:    12,
+		Message: "unterminated code fence",
+	}
+
+	assert.Equal(t, "line 12: unterminated code fence", diagnostic.String())
+}
+
+func TestParseWithoutDiagnostics(t *testing.T) {
+	doc := Parse("# Title\n\nParagraph\n\n| a |\n| --- |")
+
+	assert.Nil(t, doc.Diagnostics)
+}
+
+func TestParseTableColumnCountMismatch(t *testing.T) {
+	content := "# Title\n\n| a | b |\n| --- |\n| c | d |"
+
+	doc := Parse(content)
+
+	assert.Equal(t, []Diagnostic{
+		Diagnostic{Line: 4, Message: "table header/column count mismatch"},
+	}, doc.Diagnostics)
+}
+
+func TestParseUnterminatedCodeFence(t *testing.T) {
+	content := "Paragraph\n\n```go\nfunc main() {"
+
+	doc := Parse(content)
+
+	assert.Equal(t, []Diagnostic{
+		Diagnostic{Line: 3, Message: "unterminated code fence"},
+	}, doc.Diagnostics)
+}
+
+func TestParseDiagnosticsInNestedBlocks(t *testing.T) {
+	content := "* item\n\n  > | a | b |\n  > | --- |\n\n> quote\n>\n> ~~~\n> code"
+
+	doc := Parse(content)
+
+	assert.Equal(t, []Diagnostic{
+		Diagnostic{Line: 4, Message: "table header/column count mismatch"},
+		Diagnostic{Line: 8, Message: "unterminated code fence"},
+	}, doc.Diagnostics)
+}
+
+func TestParseWritesNothingToStdout(t *testing.T) {
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = w
+
+	Parse("Paragraph\n\n| a | b |\n| --- |\n\nNot | a\ntable")
+
+	w.Close()
+	os.Stdout = stdout
+	output, err := ioutil.ReadAll(r)
+
+	assert.NoError(t, err)
+	assert.Empty(t, string(output))
+}
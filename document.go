@@ -3,6 +3,12 @@ package parser
 // Document holds markdown document
 type Document struct {
 	*Element
+	// FrontMatter is metadata found at the very start of markdown, or nil
+	FrontMatter *FrontMatter
+	// LinkReferences holds link reference definitions by label normalized
+	// for case-insensitive matching, see LinkReference
+	LinkReferences map[string]*LinkReference
+	Diagnostics    []Diagnostic
 }
 
 // NewDocument creates a document
@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -20,14 +20,23 @@ var ElementHierarchy = map[string]int{
 	"text":           100,
 	"unordered-list": 100,
 	"ordered-list":   100,
+	"blockquote":     100,
+	"hr":             100,
+	"toc":            100,
+	"footnote":       100,
+	"html":           100,
 }
 
 // Element represents element in markdown document
 type Element struct {
-	Text     string
-	Type     string
-	Parent   *Element
-	Elements []*Element
+	Text       string
+	Type       string
+	Level      int
+	Attributes map[string]string
+	Span       Span
+	Inline     bool
+	Parent     *Element
+	Elements   []*Element
 }
 
 // NewElement creates a new element
@@ -40,8 +49,30 @@ func NewElement(elType, text string) *Element {
 	}
 }
 
-// NewTable creates a new table
-func NewTable(table [][]string) *Element {
+// NewHeading creates a new heading of level 1 to 6.
+// Explicit {#custom-id} at the end of text is recorded as id attribute,
+// marked by explicit-id attribute so that it is written back by RenderMarkdown.
+func NewHeading(level int, text string) *Element {
+	text, id := splitHeadingID(text)
+	heading := &Element{
+		Parent:   nil,
+		Text:     text,
+		Type:     "h" + strconv.Itoa(level),
+		Level:    level,
+		Elements: []*Element{},
+	}
+	if id != "" {
+		heading.Attributes = map[string]string{
+			"id":          id,
+			"explicit-id": "true",
+		}
+	}
+	return heading
+}
+
+// NewTable creates a new table.
+// Optional column alignment ("left", "center" or "right") is recorded on each cell.
+func NewTable(table [][]string, align ...string) *Element {
 	tableElement := &Element{
 		Parent:   nil,
 		Text:     "",
@@ -56,13 +87,18 @@ func NewTable(table [][]string) *Element {
 			Type:     "row",
 			Elements: []*Element{},
 		}
-		for _, cell := range row {
+		for i, cell := range row {
 			cellElement := &Element{
 				Parent:   rowElement,
 				Text:     cell,
 				Type:     "cell",
 				Elements: []*Element{},
 			}
+			if i < len(align) && align[i] != "" {
+				cellElement.Attributes = map[string]string{
+					"align": align[i],
+				}
+			}
 			rowElement.Append(cellElement)
 		}
 		tableElement.Append(rowElement)
@@ -71,50 +107,170 @@ func NewTable(table [][]string) *Element {
 	return tableElement
 }
 
-// NewUnorderedList creates unordered list
+// codeAttributePrefix prefixes keys of info string attributes so that they
+// do not collide with info, language and fence recorded by the parser
+const codeAttributePrefix = "attr-"
+
+// NewCodeBlock creates code block with its fence info string.
+// Language and attributes of info string are recorded on the element,
+// attributes under keys prefixed by attr-, see CodeAttributes.
+func NewCodeBlock(text, info string) *Element {
+	codeElement := NewElement("code", text)
+
+	info = strings.TrimSpace(unescapeMarkdown(info))
+	if info == "" {
+		return codeElement
+	}
+
+	language, attributes := parseCodeInfo(info)
+	codeElement.Attributes = map[string]string{
+		"info": info,
+	}
+	if language != "" {
+		codeElement.Attributes["language"] = language
+	}
+	for key, value := range attributes {
+		codeElement.Attributes[codeAttributePrefix+key] = value
+	}
+
+	return codeElement
+}
+
+// NewUnorderedList creates unordered list from markdown content of each item
 func NewUnorderedList(list []string) *Element {
+	return newParser().newList(repeatListMarker(listMarker{char: "*"}, len(list)), list, 1)
+}
+
+// NewOrderedList creates ordered list from markdown content of each item
+func NewOrderedList(list []string) *Element {
+	markers := repeatListMarker(listMarker{ordered: true, char: "."}, len(list))
+	for i := range markers {
+		markers[i].number = strconv.Itoa(i + 1)
+	}
+	return newParser().newList(markers, list, 1)
+}
+
+// newList creates list of items with their markers, starting at line.
+// Type and marker of the list are taken from the first item.
+func (p *parser) newList(markers []listMarker, list []string, line int) *Element {
 	listElement := &Element{
 		Parent:   nil,
 		Text:     "",
 		Type:     "unordered-list",
 		Elements: []*Element{},
 	}
+	if markers[0].ordered {
+		listElement.Type = "ordered-list"
+	}
 
-	for _, item := range list {
-		itemElement := &Element{
-			Parent:   listElement,
-			Text:     item,
-			Type:     "list-item",
-			Elements: []*Element{},
+	tight := true
+	for i, item := range list {
+		// blank line between items makes list loose
+		if i < len(list)-1 && strings.HasSuffix(item, "\n") {
+			tight = false
+		}
+		if hasBlankLineBetweenBlocks(item) {
+			tight = false
 		}
 
+		itemElement := p.newListItem(item, line)
+		itemElement.Parent = listElement
+		itemElement.Span = p.listItemSpan(markers[i], item, line)
+		line += strings.Count(item, "\n") + 1
+		if markers[i].ordered {
+			if itemElement.Attributes == nil {
+				itemElement.Attributes = map[string]string{}
+			}
+			itemElement.Attributes["number"] = normalizeListNumber(markers[i].number)
+		}
 		listElement.Append(itemElement)
 	}
 
+	listElement.Attributes = map[string]string{
+		"tight": strconv.FormatBool(tight),
+	}
+	if markers[0].ordered {
+		listElement.Attributes["delimiter"] = markers[0].char
+		listElement.Attributes["start"] = normalizeListNumber(markers[0].number)
+	} else {
+		listElement.Attributes["marker"] = markers[0].char
+	}
+
 	return listElement
 }
 
-// NewOrderedList creates ordered list
-func NewOrderedList(list []string) *Element {
-	listElement := &Element{
+// listItemSpan returns span of item from its marker to the end of its last non-blank line
+func (p *parser) listItemSpan(marker listMarker, content string, line int) Span {
+	span := p.lineSpan(line, strings.TrimRight(content, "\n"))
+	span.Start = p.locateSuffix(line, marker.line).advance(marker.indent)
+	return span
+}
+
+// normalizeListNumber removes leading zeros from ordered list number
+func normalizeListNumber(number string) string {
+	number = strings.TrimLeft(number, "0")
+	if number == "" {
+		return "0"
+	}
+	return number
+}
+
+func repeatListMarker(marker listMarker, n int) []listMarker {
+	markers := make([]listMarker, n)
+	for i := range markers {
+		markers[i] = marker
+	}
+	return markers
+}
+
+// NewListItem creates list item with its content parsed into child elements.
+// Leading paragraph of the item becomes the item text. Task marker [ ] or [x]
+// at the beginning of the text sets checked attribute.
+func NewListItem(content string) *Element {
+	return newParser().newListItem(content, 1)
+}
+
+func (p *parser) newListItem(content string, line int) *Element {
+	itemElement := &Element{
 		Parent:   nil,
 		Text:     "",
-		Type:     "ordered-list",
+		Type:     "list-item",
 		Elements: []*Element{},
 	}
 
-	for _, item := range list {
-		itemElement := &Element{
-			Parent:   listElement,
-			Text:     item,
-			Type:     "list-item",
-			Elements: []*Element{},
+	p.parseBlocks(strings.TrimRight(content, "\n"), line, itemElement)
+
+	if len(itemElement.Elements) > 0 && itemElement.Elements[0].Type == "text" {
+		itemElement.Text = itemElement.Elements[0].Text
+		itemElement.Elements = itemElement.Elements[1:]
+	}
+
+	if checked, text, ok := splitTaskMarker(itemElement.Text); ok {
+		itemElement.Text = text
+		itemElement.Attributes = map[string]string{
+			"checked": strconv.FormatBool(checked),
 		}
+	}
 
-		listElement.Append(itemElement)
+	return itemElement
+}
+
+// NewBlockquote creates blockquote with its content parsed into child elements
+func NewBlockquote(content string) *Element {
+	return newParser().newBlockquote(content, 1)
+}
+
+func (p *parser) newBlockquote(content string, line int) *Element {
+	quoteElement := &Element{
+		Parent:   nil,
+		Text:     "",
+		Type:     "blockquote",
+		Elements: []*Element{},
 	}
 
-	return listElement
+	p.parseBlocks(content, line, quoteElement)
+
+	return quoteElement
 }
 
 // Append element to current element
@@ -122,197 +278,484 @@ func (e *Element) Append(el *Element) {
 	e.Elements = append(e.Elements, el)
 }
 
-func createElement(block string) *Element {
-	if text, ok := tryH1(block); ok {
-		return NewElement("h1", text)
+// createElement creates element from markdown block starting at line
+func (p *parser) createElement(block string, line int) *Element {
+	if text, ok := tryIndentedCode(block); ok {
+		return NewCodeBlock(text, "")
 	}
-	if text, ok := tryH2(block); ok {
-		return NewElement("h2", text)
+	if isThematicBreak(block) {
+		return NewElement("hr", "")
 	}
-	if text, ok := tryH3(block); ok {
-		return NewElement("h3", text)
+	if isTOCPlaceholder(block) {
+		return NewElement("toc", strings.TrimSpace(block))
 	}
-	if text, ok := tryH4(block); ok {
-		return NewElement("h4", text)
+	if isHTMLBlock(block) {
+		return NewElement("html", block)
 	}
-	if text, ok := tryH5(block); ok {
-		return NewElement("h5", text)
-	}
-	if text, ok := tryH6(block); ok {
-		return NewElement("h6", text)
+	if level, text, ok := tryATXHeading(block); ok {
+		return NewHeading(level, text)
 	}
 	if text, ok := tryCode(block); ok {
-		return NewElement("code", text)
+		codeElement := NewCodeBlock(text, codeInfo(block))
+		fence, _ := parseCodeFence(strings.SplitN(block, "\n", 2)[0])
+		if codeElement.Attributes == nil {
+			codeElement.Attributes = map[string]string{}
+		}
+		codeElement.Attributes["fence"] = strings.Repeat(string(fence.char), fence.length)
+		return codeElement
 	}
-	if table, ok := tryTable(block); ok {
-		return NewTable(table)
+	if label, content, ok := tryFootnoteDefinition(block); ok {
+		return p.newFootnote(label, content, line)
 	}
-	if list, ok := tryUnorderedList(block); ok {
-		return NewUnorderedList(list)
+	if content, ok := tryBlockquote(block); ok {
+		return p.newBlockquote(content, line)
 	}
-	if list, ok := tryOrderedList(block); ok {
-		return NewOrderedList(list)
+	table, warning, ok := parseTable(block)
+	if ok {
+		lines := strings.Split(block, "\n")
+		tableElement := NewTable(table, tableAlignments(lines[1])...)
+		p.locateTable(tableElement, lines, line)
+		return tableElement
 	}
-	return NewElement("text", block)
+	if warning != "" {
+		p.warn(line+1, warning)
+	}
+	if markers, list, ok := splitList(block); ok {
+		return p.newList(markers, list, line)
+	}
+	if level, text, ok := trySetextHeading(block); ok {
+		return NewHeading(level, text)
+	}
+
+	// paragraph lines do not keep their indentation
+	lines := strings.Split(block, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimLeft(lines[i], " \t")
+	}
+	return NewElement("text", strings.Join(lines, "\n"))
 }
 
-func testLinePattern(pat, text string) (string, bool) {
-	re := regexp.MustCompile(pat)
-	m := re.FindAllStringSubmatch(text, -1)
-	if len(m) > 0 {
-		return m[0][1], true
+// atxHeadingPattern matches heading opened by 1 to 6 # characters
+var atxHeadingPattern = regexp.MustCompile("^ {0,3}(#{1,6})(?:[ \t]+(.*))?$")
+
+// atxClosingPattern matches optional closing sequence of ATX heading
+var atxClosingPattern = regexp.MustCompile("(^|[ \t]+)#+$")
+
+// tryATXHeading reads heading opened by 1 to 6 # characters.
+// Optional closing sequence of # characters is removed from heading text.
+func tryATXHeading(block string) (int, string, bool) {
+	m := atxHeadingPattern.FindStringSubmatch(block)
+	if m == nil {
+		return 0, "", false
 	}
-	return "", false
+
+	text := strings.TrimSpace(m[2])
+	text = strings.TrimSpace(atxClosingPattern.ReplaceAllString(text, ""))
+
+	return len(m[1]), text, true
 }
 
-func tryH1(block string) (string, bool) {
-	if text, ok := testLinePattern("^# (.+)$", block); ok {
-		return text, ok
+// trySetextHeading reads paragraph underlined by = (level 1) or - (level 2)
+func trySetextHeading(block string) (int, string, bool) {
+	lines := strings.Split(block, "\n")
+	last := len(lines) - 1
+	text := strings.TrimSpace(strings.Join(lines[:last], "\n"))
+	if last == 0 || text == "" || !isSetextUnderline(lines[last]) {
+		return 0, "", false
 	}
-	if text, ok := testLinePattern("^(.+)\n==+$", block); ok {
-		return text, ok
+
+	if strings.TrimSpace(lines[last])[0] == '=' {
+		return 1, text, true
 	}
-	return "", false
+	return 2, text, true
 }
 
-func tryH2(block string) (string, bool) {
-	if text, ok := testLinePattern("^## (.+)$", block); ok {
-		return text, ok
+// tryCode reads content of fenced code block. Indentation of opening fence
+// is removed from content lines and unterminated block runs to end of block.
+func tryCode(block string) (string, bool) {
+	lines := strings.Split(block, "\n")
+
+	fence, ok := parseCodeFence(lines[0])
+	if !ok {
+		return "", false
 	}
-	if text, ok := testLinePattern("^(.+)\n--+$", block); ok {
-		return text, ok
+
+	content := []string{}
+	for _, line := range lines[1:] {
+		if fence.isClosedBy(line) {
+			break
+		}
+		content = append(content, stripIndent(line, fence.indent))
 	}
-	return "", false
+
+	return strings.Join(content, "\n"), true
 }
 
-func tryH3(block string) (string, bool) {
-	if text, ok := testLinePattern("^### (.+)$", block); ok {
-		return text, ok
+// tryIndentedCode reads code block indented by 4 columns and removes the indentation
+func tryIndentedCode(block string) (string, bool) {
+	lines := strings.Split(block, "\n")
+	if indentation(lines[0]) < 4 {
+		return "", false
 	}
-	return "", false
-}
 
-func tryH4(block string) (string, bool) {
-	if text, ok := testLinePattern("^#### (.+)$", block); ok {
-		return text, ok
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" && indentation(line) < 4 {
+			return "", false
+		}
+		lines[i] = stripColumns(line, 4)
 	}
-	return "", false
+
+	return strings.Join(lines, "\n"), true
 }
 
-func tryH5(block string) (string, bool) {
-	if text, ok := testLinePattern("^##### (.+)$", block); ok {
-		return text, ok
-	}
-	return "", false
+// codeInfo returns info string following opening code fence
+func codeInfo(block string) string {
+	fence, _ := parseCodeFence(strings.SplitN(block, "\n", 2)[0])
+	return fence.info
 }
 
-func tryH6(block string) (string, bool) {
-	if text, ok := testLinePattern("^###### (.+)$", block); ok {
-		return text, ok
+// codeInfoPattern matches word or key=value attribute of info string
+var codeInfoPattern = regexp.MustCompile(`([^\s=]+)(?:=("[^"]*"|'[^']*'|\S*))?`)
+
+// parseCodeInfo splits info string into language and key=value attributes.
+// Language is the first word unless it is an attribute, values may be quoted
+// and attribute without value is recorded with empty value.
+func parseCodeInfo(info string) (string, map[string]string) {
+	attributes := map[string]string{}
+	language := ""
+
+	for i, m := range codeInfoPattern.FindAllStringSubmatch(info, -1) {
+		if i == 0 && !strings.Contains(m[0], "=") {
+			language = m[1]
+			continue
+		}
+		value := m[2]
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			value = value[1 : len(value)-1]
+		}
+		attributes[m[1]] = value
 	}
-	return "", false
+
+	return language, attributes
 }
 
-func tryCode(block string) (string, bool) {
-	if text, ok := testLinePattern("(?s)^```[^\n]*\n(.+)\n```$", block); ok {
-		return text, ok
+// splitList splits list block into marker and markdown content of each item.
+// Continuation lines are stripped by the item content indentation
+// and blank lines following an item are kept at the end of its content.
+// All items must share the same marker character.
+func splitList(block string) ([]listMarker, []string, bool) {
+	lines := strings.Split(block, "\n")
+
+	marker, ok := parseListMarker(lines[0])
+	if !ok {
+		return nil, nil, false
 	}
-	if text, ok := testLinePattern("(?s)^~~~[^\n]*\n(.+)\n~~~$", block); ok {
-		return text, ok
+
+	markers := []listMarker{marker}
+	output := []string{}
+	item := []string{marker.content}
+	for _, line := range lines[1:] {
+		if next, ok := parseListMarker(line); ok && next.indent < marker.contentIndent {
+			if next.char != marker.char {
+				return nil, nil, false
+			}
+			output = append(output, strings.Join(item, "\n"))
+			markers = append(markers, next)
+			item = []string{next.content}
+			marker = next
+			continue
+		}
+		item = append(item, stripColumns(line, marker.contentIndent))
 	}
-	return "", false
+	output = append(output, strings.Join(item, "\n"))
+
+	return markers, output, true
 }
 
-func tryUnorderedList(block string) ([]string, bool) {
-	output := []string{}
-	lines := strings.Split(block, "\n")
+// listMarker describes list item marker at beginning of line.
+// Char is the bullet of unordered item or the delimiter of ordered item.
+type listMarker struct {
+	ordered       bool
+	char          string
+	number        string
+	indent        int
+	contentIndent int
+	content       string
+	line          string
+}
+
+// listMarkerPattern matches bullet or ordered list item marker and its content
+var listMarkerPattern = regexp.MustCompile("^( {0,3})([*+-]|(\\d+)([.)]))(?:([ \t]+)(.*))?$")
 
-	for _, line := range lines {
-		if text, ok := testLinePattern("^\\* (.+)$", line); ok {
-			output = append(output, text)
+// parseListMarker detects list item marker with up to 3 spaces of indentation
+func parseListMarker(line string) (listMarker, bool) {
+	m := listMarkerPattern.FindStringSubmatch(line)
+	if m == nil {
+		return listMarker{}, false
+	}
+
+	// spaces count columns after marker, tab advancing to next tab stop
+	start := len(m[1]) + len(m[2])
+	spaces, content := 0, m[6]
+	for _, c := range m[5] {
+		if c == '\t' {
+			spaces += 4 - (start+spaces)%4
 		} else {
-			return nil, false
+			spaces++
 		}
 	}
+	if spaces > 4 {
+		// item starting with indented code only consumes one space
+		content = strings.Repeat(" ", spaces-1) + content
+		spaces = 1
+	}
+	if content == "" {
+		spaces = 1
+	}
+
+	char := m[2]
+	if m[3] != "" {
+		char = m[4]
+	}
 
-	return output, true
+	return listMarker{
+		ordered:       m[3] != "",
+		char:          char,
+		number:        m[3],
+		indent:        len(m[1]),
+		contentIndent: start + spaces,
+		content:       content,
+		line:          line,
+	}, true
 }
 
-func tryOrderedList(block string) ([]string, bool) {
-	output := []string{}
+// stripColumns removes up to n leading columns of spaces and tabs from line.
+// Tab crossing column n is partially kept as spaces, and so are tabs left in
+// indentation when stripping moves their tab stops.
+func stripColumns(line string, n int) string {
+	columns, i := 0, 0
+	for ; i < len(line) && columns < n; i++ {
+		if line[i] == ' ' {
+			columns++
+		} else if line[i] == '\t' {
+			columns += 4 - columns%4
+		} else {
+			break
+		}
+	}
+
+	rest := line[i:]
+	if columns < n {
+		return rest
+	}
+	indent := len(rest) - len(strings.TrimLeft(rest, " \t"))
+	if columns == n && (n%4 == 0 || !strings.Contains(rest[:indent], "\t")) {
+		return rest
+	}
+	return strings.Repeat(" ", indentation(line[:i+indent])-n) + rest[indent:]
+}
+
+// stripIndent removes up to n leading spaces from line
+func stripIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return line[i:]
+}
+
+// hasBlankLineBetweenBlocks checks whether blocks of content are separated by blank line
+func hasBlankLineBetweenBlocks(content string) bool {
+	tokenizer := NewTokenizer()
+	tokenizer.Tokenize(strings.TrimRight(content, "\n"))
+
+	for i := 1; i < len(tokenizer.Tokens); i++ {
+		prev := tokenizer.Tokens[i-1]
+		if tokenizer.Tokens[i].Line > prev.Line+strings.Count(prev.Text, "\n")+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// blockquotePattern matches line of blockquote and its content after > marker
+var blockquotePattern = regexp.MustCompile("^ {0,3}> ?(.*)$")
+
+func tryBlockquote(block string) (string, bool) {
 	lines := strings.Split(block, "\n")
 
-	for _, line := range lines {
-		if text, ok := testLinePattern("^\\d+\\. (.+)$", line); ok {
-			output = append(output, text)
-		} else {
-			return nil, false
+	if !blockquotePattern.MatchString(lines[0]) {
+		return "", false
+	}
+
+	for i, line := range lines {
+		if m := blockquotePattern.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1]
 		}
+		// line without marker is lazy continuation of quoted paragraph
 	}
 
-	return output, true
+	return strings.Join(lines, "\n"), true
 }
 
-func tryTable(block string) ([][]string, bool) {
+// tableSeparatorPattern matches cell of table header separator row
+var tableSeparatorPattern = regexp.MustCompile("^:?-+:?$")
+
+// parseTable parses table block. Warning describes why block
+// that looks like a table cannot be parsed as one.
+func parseTable(block string) ([][]string, string, bool) {
 	output := [][]string{}
 
 	lines := strings.Split(block, "\n")
-	if len(lines) < 3 {
-		fmt.Println("not a table")
-		return nil, false
+	if len(lines) < 2 || !hasTablePipe(lines[0]) || !hasTablePipe(lines[1]) {
+		// not a table
+		return nil, "", false
 	}
 
 	// check header separator
-	// header separator for one column
-	patSep := "^\\|( :?---+? \\|)+$"
-	reSep := regexp.MustCompile(patSep)
-	if !reSep.MatchString(lines[1]) {
-		// header separator does not present
-		fmt.Println("header separator does not present")
-		return nil, false
+	separator := splitTableRow(lines[1])
+	for _, cell := range separator {
+		if !tableSeparatorPattern.MatchString(cell) {
+			// header separator does not present
+			return nil, "", false
+		}
+	}
+
+	header := splitTableRow(lines[0])
+	colCount := len(header)
+
+	if colCount != len(separator) {
+		return nil, "table header/column count mismatch", false
 	}
 
-	colCount := columnCount(lines[1])
+	output = append(output, header)
 
-	if colCount != columnCount(lines[0]) {
-		// header and column count does not match
-		fmt.Println("header and column count does not match")
-		return nil, false
+	for _, line := range lines[2:] {
+		row := splitTableRow(line)
+		// body row is padded or truncated to header width
+		for len(row) < colCount {
+			row = append(row, "")
+		}
+		output = append(output, row[:colCount])
 	}
 
-	row := []string{}
-	for i := 0; i < colCount; i++ {
-		row = append(row, getCellValue(i, lines[0]))
+	return output, "", true
+}
+
+// locateTable sets span of table rows and cells. Separator line is skipped.
+func (p *parser) locateTable(table *Element, lines []string, line int) {
+	for i, row := range table.Elements {
+		k := i
+		if i > 0 {
+			k++
+		}
+		row.Span = p.lineSpan(line+k, lines[k])
+
+		_, ranges := scanTableRow(lines[k])
+		for j, cell := range row.Elements {
+			if j >= len(ranges) {
+				// padding cell missing from source row
+				cell.Span = Span{Start: row.Span.End, End: row.Span.End}
+				continue
+			}
+			cell.Span = Span{
+				Start: row.Span.Start.advance(ranges[j].start),
+				End:   row.Span.Start.advance(ranges[j].end),
+			}
+		}
 	}
-	output = append(output, row)
+}
 
-	for i, n := 2, len(lines); i < n; i++ {
-		row = []string{}
-		for j := 0; j < colCount; j++ {
-			row = append(row, getCellValue(j, lines[i]))
+// tableAlignments reads column alignment from table header separator
+func tableAlignments(line string) []string {
+	output := []string{}
+	for _, sep := range splitTableRow(line) {
+		left, right := strings.HasPrefix(sep, ":"), strings.HasSuffix(sep, ":")
+		switch {
+		case left && right:
+			output = append(output, "center")
+		case left:
+			output = append(output, "left")
+		case right:
+			output = append(output, "right")
+		default:
+			output = append(output, "")
 		}
-		output = append(output, row)
 	}
+	return output
+}
+
+// splitTableRow splits table row into trimmed cell values.
+// Leading and trailing pipes are optional and escaped pipe \| is kept in cell as |.
+func splitTableRow(line string) []string {
+	cells, _ := scanTableRow(line)
+	return cells
+}
 
-	return output, true
+// tableCellRange is byte range of trimmed cell in its row line
+type tableCellRange struct {
+	start int
+	end   int
 }
 
-func getCellValue(index int, line string) string {
-	cols := strings.Split(line, "|")
-	if cols[0] == "" { // detect left boundary pipe
-		cols = cols[1:]
+// scanTableRow splits table row into trimmed cell values and records where each cell is in line
+func scanTableRow(line string) ([]string, []tableCellRange) {
+	begin := len(line) - len(strings.TrimLeft(line, " \t"))
+	end := len(strings.TrimRight(line, " \t"))
+	if begin < end && line[begin] == '|' {
+		begin++
+	}
+	if begin < end && line[end-1] == '|' {
+		// trailing pipe is escaped when preceded by odd number of backslashes
+		backslashes := end - 1 - len(strings.TrimRight(line[:end-1], "\\"))
+		if backslashes%2 == 0 {
+			end--
+		}
 	}
-	if cols[len(cols)-1] == "" { // detect right boundary pipe
-		cols = cols[0 : len(cols)-1]
+
+	cells := []string{}
+	ranges := []tableCellRange{}
+	cell := strings.Builder{}
+	cellStart := begin
+	addCell := func(cellEnd int) {
+		raw := line[cellStart:cellEnd]
+		start := cellStart + len(raw) - len(strings.TrimLeft(raw, " \t"))
+		stop := cellStart + len(strings.TrimRight(raw, " \t"))
+		if stop < start {
+			stop = start
+		}
+		cells = append(cells, strings.TrimSpace(cell.String()))
+		ranges = append(ranges, tableCellRange{start: start, end: stop})
+		cell.Reset()
+		cellStart = cellEnd + 1
+	}
+	for i := begin; i < end; i++ {
+		switch {
+		case line[i] == '\\' && i+1 < end:
+			if line[i+1] != '|' {
+				cell.WriteByte('\\')
+			}
+			cell.WriteByte(line[i+1])
+			i++
+		case line[i] == '|':
+			addCell(i)
+		default:
+			cell.WriteByte(line[i])
+		}
 	}
+	addCell(end)
 
-	return strings.TrimSpace(cols[index])
+	return cells, ranges
 }
 
-func columnCount(line string) int {
-	colCount := strings.Count(line, "|") - 1
-	if line[0] != '|' || line[len(line)-1] != '|' {
-		colCount++
+// hasTablePipe checks whether line contains unescaped pipe
+func hasTablePipe(line string) bool {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if line[i] == '|' {
+			return true
+		}
 	}
-	return colCount
+	return false
 }
@@ -19,76 +19,90 @@ func TestCreateNewElement(t *testing.T) {
 	assert.Equal(t, expected, el)
 }
 
-func TestTryH1(t *testing.T) {
-	content := "# Title"
-
-	text, success := tryH1(content)
-
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
-}
-
-func TestTryAlternateH1(t *testing.T) {
-	content := "Title\n=="
-
-	text, success := tryH1(content)
-
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
-}
-
-func TestTryH2(t *testing.T) {
-	content := "## Title"
-
-	text, success := tryH2(content)
-
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
-}
-
-func TestTryAlternateH2(t *testing.T) {
-	content := "Title\n--"
+func TestCreateNewHeading(t *testing.T) {
+	expected := &Element{
+		Text:     "Title",
+		Type:     "h3",
+		Level:    3,
+		Parent:   nil,
+		Elements: []*Element{},
+	}
 
-	text, success := tryH2(content)
+	el := NewHeading(3, "Title")
 
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
+	assert.Equal(t, expected, el)
 }
 
-func TestTryH3(t *testing.T) {
-	content := "### Title"
+func TestTryATXHeading(t *testing.T) {
+	cases := []struct {
+		content string
+		level   int
+		text    string
+	}{
+		{"# Title", 1, "Title"},
+		{"## Title", 2, "Title"},
+		{"### Title", 3, "Title"},
+		{"#### Title", 4, "Title"},
+		{"##### Title", 5, "Title"},
+		{"###### Title", 6, "Title"},
+		{"# Title #", 1, "Title"},
+		{"## Title ##########", 2, "Title"},
+		{"### Title ###   ", 3, "Title"},
+		{"# Title#", 1, "Title#"},
+		{"### Title \\###", 3, "Title \\###"},
+		{"#   Title   ", 1, "Title"},
+		{"   ### Title", 3, "Title"},
+		{"#\tTitle", 1, "Title"},
+		{"#", 1, ""},
+		{"## ", 2, ""},
+		{"### ###", 3, ""},
+	}
 
-	text, success := tryH3(content)
+	for _, c := range cases {
+		level, text, success := tryATXHeading(c.content)
 
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
+		assert.True(t, success, c.content)
+		assert.Equal(t, c.level, level, c.content)
+		assert.Equal(t, c.text, text, c.content)
+	}
 }
 
-func TestTryH4(t *testing.T) {
-	content := "#### Title"
+func TestTryATXHeadingNotHeading(t *testing.T) {
+	for _, content := range []string{"####### Title", "#hashtag", "#5 bolt", "    # Title", "\\# Title", "# Title\nText"} {
+		_, _, success := tryATXHeading(content)
 
-	text, success := tryH4(content)
-
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
+		assert.False(t, success, content)
+	}
 }
 
-func TestTryH5(t *testing.T) {
-	content := "##### Title"
+func TestTrySetextHeading(t *testing.T) {
+	cases := []struct {
+		content string
+		level   int
+		text    string
+	}{
+		{"Title\n==", 1, "Title"},
+		{"Title\n=", 1, "Title"},
+		{"Title\n--", 2, "Title"},
+		{"  Title  \n   ---   ", 2, "Title"},
+		{"Multi line\ntitle\n===", 1, "Multi line\ntitle"},
+	}
 
-	text, success := tryH5(content)
+	for _, c := range cases {
+		level, text, success := trySetextHeading(c.content)
 
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
+		assert.True(t, success, c.content)
+		assert.Equal(t, c.level, level, c.content)
+		assert.Equal(t, c.text, text, c.content)
+	}
 }
 
-func TestTryH6(t *testing.T) {
-	content := "###### Title"
-
-	text, success := tryH6(content)
+func TestTrySetextHeadingNotHeading(t *testing.T) {
+	for _, content := range []string{"===", "Title", "Title\n= =", "Title\n    ---"} {
+		_, _, success := trySetextHeading(content)
 
-	assert.True(t, success)
-	assert.Equal(t, "Title", text)
+		assert.False(t, success, content)
+	}
 }
 
 func TestTryCodeBlock(t *testing.T) {
@@ -112,7 +126,7 @@ func TestTryAlternativeCodeBlock(t *testing.T) {
 func TestTry1ColumnTable(t *testing.T) {
 	content := "| Header |\n| --- |\n| Body |"
 
-	table, success := tryTable(content)
+	table, _, success := parseTable(content)
 
 	assert.True(t, success)
 	assert.Equal(t, [][]string{
@@ -128,7 +142,7 @@ func TestTry1ColumnTable(t *testing.T) {
 func TestTry2ColumnTable(t *testing.T) {
 	content := "| Header 1 | Header 2 |\n| --- | --- |\n| Body 1 | Body 2 |"
 
-	table, success := tryTable(content)
+	table, _, success := parseTable(content)
 
 	assert.True(t, success)
 	assert.Equal(t, [][]string{
@@ -146,7 +160,7 @@ func TestTry2ColumnTable(t *testing.T) {
 func TestTryLargeColumnTable(t *testing.T) {
 	content := "| Header 1 | Header 2 | Header 3 | Header 4 |\n| --- | --- | --- | --- |\n| Body 1 Row 1 | Body 2 Row 1 | Body 3 Row 1 | Body 4 Row 1 |\n| Body 1 Row 2 | Body 2 Row 2 | Body 3 Row 2 | Body 4 Row 2 |\n| Body 1 Row 3 | Body 2 Row 3 | Body 3 Row 3 | Body 4 Row 3 |"
 
-	table, success := tryTable(content)
+	table, _, success := parseTable(content)
 
 	assert.True(t, success)
 	assert.Equal(t, [][]string{
@@ -180,9 +194,10 @@ func TestTryLargeColumnTable(t *testing.T) {
 func TestTryUnorderedList(t *testing.T) {
 	content := "* test 1\n* test 2\n* test 3"
 
-	list, success := tryUnorderedList(content)
+	markers, list, success := splitList(content)
 
 	assert.True(t, success)
+	assert.False(t, markers[0].ordered)
 	assert.Equal(t, []string{
 		"test 1",
 		"test 2",
@@ -193,12 +208,494 @@ func TestTryUnorderedList(t *testing.T) {
 func TestTryOrderedList(t *testing.T) {
 	content := "213411. test 1\n0. test 2\n12387192837182738127391287398123. test 3"
 
-	list, success := tryOrderedList(content)
+	markers, list, success := splitList(content)
 
 	assert.True(t, success)
+	assert.True(t, markers[0].ordered)
 	assert.Equal(t, []string{
 		"test 1",
 		"test 2",
 		"test 3",
 	}, list)
 }
+
+func TestTryBlockquote(t *testing.T) {
+	content := "> # Title\n>\n> Quoted\n>> Nested"
+
+	text, success := tryBlockquote(content)
+
+	assert.True(t, success)
+	assert.Equal(t, "# Title\n\nQuoted\n> Nested", text)
+}
+
+func TestTryBlockquoteWithLazyContinuation(t *testing.T) {
+	content := "> Quoted\ncontinues"
+
+	text, success := tryBlockquote(content)
+
+	assert.True(t, success)
+	assert.Equal(t, "Quoted\ncontinues", text)
+}
+
+func TestTryNonBlockquote(t *testing.T) {
+	content := "Not quoted\n> quoted"
+
+	_, success := tryBlockquote(content)
+
+	assert.False(t, success)
+}
+
+func TestTryNestedUnorderedList(t *testing.T) {
+	content := "* item 1\n  continues\n  * nested\n* item 2\n\n  paragraph"
+
+	markers, list, success := splitList(content)
+
+	assert.True(t, success)
+	assert.False(t, markers[0].ordered)
+	assert.Equal(t, []string{
+		"item 1\ncontinues\n* nested",
+		"item 2\n\nparagraph",
+	}, list)
+}
+
+func TestTryOrderedListWithWideContentIndent(t *testing.T) {
+	content := "1.  item 1\n    continues\n10. item 2"
+
+	markers, list, success := splitList(content)
+
+	assert.True(t, success)
+	assert.True(t, markers[0].ordered)
+	assert.Equal(t, []string{
+		"item 1\ncontinues",
+		"item 2",
+	}, list)
+}
+
+func TestTryMixedListIsNotList(t *testing.T) {
+	content := "* item 1\n1. item 2"
+
+	_, _, success := splitList(content)
+
+	assert.False(t, success)
+}
+
+func TestCreateListItemWithNestedList(t *testing.T) {
+	item := NewListItem("item\n* nested 1\n* nested 2")
+
+	assert.Equal(t, "item", item.Text)
+	assert.Len(t, item.Elements, 1)
+	assert.Equal(t, "unordered-list", item.Elements[0].Type)
+	assert.Equal(t, item, item.Elements[0].Parent)
+	assert.Len(t, item.Elements[0].Elements, 2)
+}
+
+func TestCreateListItemStartingWithCode(t *testing.T) {
+	item := NewListItem("```\ncode\n```")
+
+	assert.Equal(t, "", item.Text)
+	assert.Len(t, item.Elements, 1)
+	assert.Equal(t, "code", item.Elements[0].Type)
+}
+
+func TestCreateTightList(t *testing.T) {
+	list := NewUnorderedList([]string{"item 1", "item 2\n* nested"})
+
+	assert.Equal(t, "true", list.Attributes["tight"])
+}
+
+func TestCreateLooseListWithBlankLineBetweenItems(t *testing.T) {
+	list := NewUnorderedList([]string{"item 1\n", "item 2"})
+
+	assert.Equal(t, "false", list.Attributes["tight"])
+	assert.Equal(t, "item 1", list.Elements[0].Text)
+}
+
+func TestCreateLooseListWithBlankLineInsideItem(t *testing.T) {
+	list := NewOrderedList([]string{"item 1\n\nparagraph"})
+
+	assert.Equal(t, "false", list.Attributes["tight"])
+	assert.Len(t, list.Elements[0].Elements, 1)
+	assert.Equal(t, "paragraph", list.Elements[0].Elements[0].Text)
+}
+
+func TestCreateTightListWithBlankLineInNestedList(t *testing.T) {
+	list := NewUnorderedList([]string{"item 1\n* nested 1\n\n* nested 2"})
+
+	assert.Equal(t, "true", list.Attributes["tight"])
+	assert.Equal(t, "false", list.Elements[0].Elements[0].Attributes["tight"])
+}
+
+func TestTryUnorderedListWithDashAndPlusBullets(t *testing.T) {
+	for _, bullet := range []string{"-", "+", "*"} {
+		content := bullet + " test 1\n" + bullet + " test 2"
+
+		markers, list, success := splitList(content)
+
+		assert.True(t, success, bullet)
+		assert.False(t, markers[0].ordered, bullet)
+		assert.Equal(t, []string{"test 1", "test 2"}, list, bullet)
+	}
+}
+
+func TestTryOrderedListWithParenthesisDelimiter(t *testing.T) {
+	content := "1) test 1\n2) test 2"
+
+	markers, list, success := splitList(content)
+
+	assert.True(t, success)
+	assert.True(t, markers[0].ordered)
+	assert.Equal(t, []string{"test 1", "test 2"}, list)
+}
+
+func TestTryListWithChangedMarkerIsNotList(t *testing.T) {
+	_, _, success := splitList("- test 1\n* test 2")
+	assert.False(t, success)
+
+	_, _, success = splitList("1. test 1\n2) test 2")
+	assert.False(t, success)
+}
+
+func TestParseListMarker(t *testing.T) {
+	marker, success := parseListMarker("  12) item")
+
+	assert.True(t, success)
+	assert.Equal(t, listMarker{
+		ordered:       true,
+		char:          ")",
+		number:        "12",
+		indent:        2,
+		contentIndent: 6,
+		content:       "item",
+		line:          "  12) item",
+	}, marker)
+}
+
+func TestParseListMarkerFollowedByTab(t *testing.T) {
+	for line, contentIndent := range map[string]int{"-\tfoo": 4, "1.\tone": 4, " -\tfoo": 4, "10.\tten": 4} {
+		marker, success := parseListMarker(line)
+
+		assert.True(t, success, line)
+		assert.Equal(t, contentIndent, marker.contentIndent, line)
+	}
+
+	marker, success := parseListMarker("-\t\tfoo")
+
+	assert.True(t, success)
+	assert.Equal(t, 2, marker.contentIndent)
+	assert.Equal(t, "      foo", marker.content)
+}
+
+func TestParseNonListMarker(t *testing.T) {
+	for _, line := range []string{"*emphasis*", "---", "1.5 apples", "    - indented code", "-- dashes"} {
+		_, success := parseListMarker(line)
+
+		assert.False(t, success, line)
+	}
+}
+
+func TestCreateListRecordsMarker(t *testing.T) {
+	unordered := newParser().createElement("+ item 1\n+ item 2", 1)
+	ordered := newParser().createElement("1) item 1\n2) item 2", 1)
+
+	assert.Equal(t, "unordered-list", unordered.Type)
+	assert.Equal(t, "+", unordered.Attributes["marker"])
+	assert.Equal(t, "ordered-list", ordered.Type)
+	assert.Equal(t, ")", ordered.Attributes["delimiter"])
+}
+
+func TestCreateOrderedListRecordsNumbers(t *testing.T) {
+	list := newParser().createElement("007. item 1\n8. item 2\n10. item 3", 1)
+
+	assert.Equal(t, "7", list.Attributes["start"])
+	assert.Equal(t, "7", list.Elements[0].Attributes["number"])
+	assert.Equal(t, "8", list.Elements[1].Attributes["number"])
+	assert.Equal(t, "10", list.Elements[2].Attributes["number"])
+}
+
+func TestCreateOrderedListNumbersFromOne(t *testing.T) {
+	list := NewOrderedList([]string{"item 1", "item 2"})
+
+	assert.Equal(t, "1", list.Attributes["start"])
+	assert.Equal(t, "1", list.Elements[0].Attributes["number"])
+	assert.Equal(t, "2", list.Elements[1].Attributes["number"])
+}
+
+func TestCreateOrderedListStartingAtZero(t *testing.T) {
+	list := newParser().createElement("0. item", 1)
+
+	assert.Equal(t, "0", list.Attributes["start"])
+}
+
+func TestTryAlignedTable(t *testing.T) {
+	content := "| Left | Center | Right |\n| :--- | :---: | ---: |\n| a | b | c |"
+
+	table, _, success := parseTable(content)
+
+	assert.True(t, success)
+	assert.Equal(t, [][]string{
+		[]string{"Left", "Center", "Right"},
+		[]string{"a", "b", "c"},
+	}, table)
+}
+
+func TestTableAlignments(t *testing.T) {
+	alignments := tableAlignments("| :--- | :---: | ---: | --- |")
+
+	assert.Equal(t, []string{"left", "center", "right", ""}, alignments)
+}
+
+func TestCreateTableWithAlignment(t *testing.T) {
+	table := NewTable([][]string{
+		[]string{"Header 1", "Header 2"},
+		[]string{"Body 1", "Body 2"},
+	}, "center", "")
+
+	for _, row := range table.Elements {
+		assert.Equal(t, map[string]string{"align": "center"}, row.Elements[0].Attributes)
+		assert.Nil(t, row.Elements[1].Attributes)
+	}
+}
+
+func TestTryTableWithoutOuterPipes(t *testing.T) {
+	content := "Header 1 | Header 2\n--- | ---\nBody 1 | Body 2"
+
+	table, _, success := parseTable(content)
+
+	assert.True(t, success)
+	assert.Equal(t, [][]string{
+		[]string{"Header 1", "Header 2"},
+		[]string{"Body 1", "Body 2"},
+	}, table)
+}
+
+func TestTryTableWithFlexibleSeparator(t *testing.T) {
+	content := "|Header 1|Header 2|\n|-|:-:|\n|Body 1|Body 2|"
+
+	table, _, success := parseTable(content)
+
+	assert.True(t, success)
+	assert.Equal(t, [][]string{
+		[]string{"Header 1", "Header 2"},
+		[]string{"Body 1", "Body 2"},
+	}, table)
+}
+
+func TestTryTableWithEscapedPipes(t *testing.T) {
+	content := "| Code | Text |\n| --- | --- |\n| `a \\| b` | x \\| y \\\\ |"
+
+	table, _, success := parseTable(content)
+
+	assert.True(t, success)
+	assert.Equal(t, [][]string{
+		[]string{"Code", "Text"},
+		[]string{"`a | b`", "x | y \\\\"},
+	}, table)
+}
+
+func TestTryTableWithRaggedRows(t *testing.T) {
+	content := "| a | b |\n| --- | --- |\n| short |\n| c | d | extra |"
+
+	table, _, success := parseTable(content)
+
+	assert.True(t, success)
+	assert.Equal(t, [][]string{
+		[]string{"a", "b"},
+		[]string{"short", ""},
+		[]string{"c", "d"},
+	}, table)
+}
+
+func TestTryTableWithHeaderOnly(t *testing.T) {
+	content := "| a | b |\n| --- | --- |"
+
+	table, _, success := parseTable(content)
+
+	assert.True(t, success)
+	assert.Equal(t, [][]string{
+		[]string{"a", "b"},
+	}, table)
+}
+
+func TestTryTableWithMismatchedHeader(t *testing.T) {
+	content := "| a | b |\n| --- |\n| c | d |"
+
+	_, _, success := parseTable(content)
+
+	assert.False(t, success)
+}
+
+func TestTrySetextHeadingIsNotTable(t *testing.T) {
+	content := "Title\n---"
+
+	_, _, success := parseTable(content)
+
+	assert.False(t, success)
+}
+
+func TestSplitTableRow(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, splitTableRow("| a | b |"))
+	assert.Equal(t, []string{"a", "b"}, splitTableRow("a | b"))
+	assert.Equal(t, []string{"a", "b |"}, splitTableRow("a | b \\|"))
+	assert.Equal(t, []string{"", "b"}, splitTableRow("| | b |"))
+	assert.Equal(t, []string{"a", "b \\\\"}, splitTableRow("a | b \\\\|"))
+}
+
+func TestCodeInfo(t *testing.T) {
+	assert.Equal(t, "go", codeInfo("```go\nfmt.Println()\n```"))
+	assert.Equal(t, " python title=\"x\"", codeInfo("~~~ python title=\"x\"\nprint()\n~~~"))
+	assert.Equal(t, "", codeInfo("```\ncode\n```"))
+}
+
+func TestParseCodeInfo(t *testing.T) {
+	language, attributes := parseCodeInfo("python title=\"hello world.py\" linenos start='3' hl=1-2")
+
+	assert.Equal(t, "python", language)
+	assert.Equal(t, map[string]string{
+		"title":   "hello world.py",
+		"linenos": "",
+		"start":   "3",
+		"hl":      "1-2",
+	}, attributes)
+}
+
+func TestParseCodeInfoWithoutLanguage(t *testing.T) {
+	language, attributes := parseCodeInfo("title=\"x\"")
+
+	assert.Equal(t, "", language)
+	assert.Equal(t, map[string]string{
+		"title": "x",
+	}, attributes)
+}
+
+func TestCreateCodeBlockWithInfo(t *testing.T) {
+	code := NewCodeBlock("print()", " python title=\"x\" ")
+
+	assert.Equal(t, &Element{
+		Text: "print()",
+		Type: "code",
+		Attributes: map[string]string{
+			"info":       "python title=\"x\"",
+			"language":   "python",
+			"attr-title": "x",
+		},
+		Elements: []*Element{},
+	}, code)
+}
+
+func TestCreateCodeBlockWithoutInfo(t *testing.T) {
+	code := NewCodeBlock("code", "")
+
+	assert.Nil(t, code.Attributes)
+}
+
+func TestCreateCodeElementFromFence(t *testing.T) {
+	code := newParser().createElement("```go\nfunc main() {}\n```", 1)
+
+	assert.Equal(t, "code", code.Type)
+	assert.Equal(t, "func main() {}", code.Text)
+	assert.Equal(t, "go", code.Attributes["language"])
+	assert.Equal(t, "go", code.Attributes["info"])
+}
+
+func TestCreateCodeElementWithReservedAttributeNames(t *testing.T) {
+	code := newParser().createElement("```go language=z info=y fence=x\ncode\n```", 1)
+
+	assert.Equal(t, "go", code.CodeLanguage())
+	assert.Equal(t, "go language=z info=y fence=x", code.CodeInfo())
+	assert.Equal(t, "```", code.Attributes["fence"])
+	assert.Equal(t, map[string]string{
+		"language": "z",
+		"info":     "y",
+		"fence":    "x",
+	}, code.CodeAttributes())
+
+	code = newParser().createElement("```info=y\ncode\n```", 1)
+
+	assert.Equal(t, "", code.CodeLanguage())
+	assert.Equal(t, "info=y", code.CodeInfo())
+	assert.Equal(t, map[string]string{
+		"info": "y",
+	}, code.CodeAttributes())
+}
+
+func TestTryCodeBlockWithIndentedFence(t *testing.T) {
+	content := "  ```\n  Content\n    Indented\nNot indented\n  ```"
+
+	text, success := tryCode(content)
+
+	assert.True(t, success)
+	assert.Equal(t, "Content\n  Indented\nNot indented", text)
+}
+
+func TestTryUnterminatedCodeBlock(t *testing.T) {
+	content := "~~~~\nContent\n~~~"
+
+	text, success := tryCode(content)
+
+	assert.True(t, success)
+	assert.Equal(t, "Content\n~~~", text)
+}
+
+func TestParseCodeFence(t *testing.T) {
+	fence, success := parseCodeFence("  ````go run")
+
+	assert.True(t, success)
+	assert.Equal(t, codeFence{char: '`', length: 4, indent: 2, info: "go run"}, fence)
+	assert.True(t, fence.isClosedBy("`````  "))
+	assert.False(t, fence.isClosedBy("```"))
+	assert.False(t, fence.isClosedBy("~~~~"))
+	assert.False(t, fence.isClosedBy("    ````"))
+}
+
+func TestTryIndentedCode(t *testing.T) {
+	content := "    func main() {\n        return\n  \n    }"
+
+	text, success := tryIndentedCode(content)
+
+	assert.True(t, success)
+	assert.Equal(t, "func main() {\n    return\n\n}", text)
+}
+
+func TestTryIndentedCodeWithTab(t *testing.T) {
+	text, success := tryIndentedCode("\tfoo\n  \tbar")
+
+	assert.True(t, success)
+	assert.Equal(t, "foo\nbar", text)
+}
+
+func TestTryIndentedCodeNotIndented(t *testing.T) {
+	_, success := tryIndentedCode("   foo\n    bar")
+
+	assert.False(t, success)
+}
+
+func TestCreateCodeElementFromIndentedCode(t *testing.T) {
+	code := newParser().createElement("    # not a heading\n    *not a list*", 1)
+
+	assert.Equal(t, &Element{
+		Text:     "# not a heading\n*not a list*",
+		Type:     "code",
+		Elements: []*Element{},
+	}, code)
+}
+
+func TestStripColumns(t *testing.T) {
+	assert.Equal(t, "foo", stripColumns("    foo", 4))
+	assert.Equal(t, "  foo", stripColumns("      foo", 4))
+	assert.Equal(t, "foo", stripColumns("  \tfoo", 4))
+	assert.Equal(t, "  foo", stripColumns(" \tfoo", 2))
+	assert.Equal(t, "", stripColumns("  ", 4))
+	assert.Equal(t, "\tfoo", stripColumns("  \t\tfoo", 4))
+	assert.Equal(t, "      foo", stripColumns("\t\tfoo", 2))
+	assert.Equal(t, "  foo", stripColumns("  \tfoo", 2))
+}
+
+func TestCreateThematicBreak(t *testing.T) {
+	hr := newParser().createElement("* * *", 1)
+
+	assert.Equal(t, &Element{
+		Text:     "",
+		Type:     "hr",
+		Elements: []*Element{},
+	}, hr)
+}
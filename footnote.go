@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Footnote is a footnote definition with its references, numbered in order of first reference
+type Footnote struct {
+	Label      string
+	Number     int
+	Definition *Element
+	References []*Element
+}
+
+// footnoteDefinitionPattern matches [^label]: starting footnote definition
+var footnoteDefinitionPattern = regexp.MustCompile(`^ {0,3}\[\^([^\]\s]+)\]:[ \t]*`)
+
+// isFootnoteDefinitionStart checks whether line starts footnote definition
+func isFootnoteDefinitionStart(line string) bool {
+	return footnoteDefinitionPattern.MatchString(line)
+}
+
+// tryFootnoteDefinition separates label and content of footnote definition.
+// Content lines following the first one lose up to 4 columns of indentation.
+func tryFootnoteDefinition(block string) (string, string, bool) {
+	lines := strings.Split(block, "\n")
+	m := footnoteDefinitionPattern.FindStringSubmatchIndex(lines[0])
+	if m == nil {
+		return "", "", false
+	}
+
+	label := lines[0][m[2]:m[3]]
+	lines[0] = lines[0][m[1]:]
+	for i := 1; i < len(lines); i++ {
+		lines[i] = stripColumns(lines[i], 4)
+	}
+	return label, strings.Join(lines, "\n"), true
+}
+
+// NewFootnote creates footnote definition with its content parsed into child elements
+func NewFootnote(label, content string) *Element {
+	return newParser().newFootnote(label, content, 1)
+}
+
+func (p *parser) newFootnote(label, content string, line int) *Element {
+	footnoteElement := &Element{
+		Parent:   nil,
+		Text:     "",
+		Type:     "footnote",
+		Elements: []*Element{},
+		Attributes: map[string]string{
+			"label": label,
+		},
+	}
+
+	p.parseBlocks(strings.TrimRight(content, "\n"), line, footnoteElement)
+
+	key := footnoteKey(label)
+	if _, ok := p.footnotes[key]; ok {
+		p.warn(line, "duplicate footnote definition [^"+label+"]")
+	} else {
+		p.footnotes[key] = footnoteElement
+	}
+
+	return footnoteElement
+}
+
+// footnoteKey normalizes label so that labels differing in case match
+func footnoteKey(label string) string {
+	return strings.ToLower(label)
+}
+
+// footnoteDefinitions returns the first definition of each label under root
+func footnoteDefinitions(root *Element) map[string]*Element {
+	definitions := map[string]*Element{}
+	Walk(root, func(el *Element, entering bool) WalkStatus {
+		if el.Inline {
+			return WalkSkipChildren
+		}
+		if entering && el.Kind() == KindFootnote {
+			key := footnoteKey(el.Attributes["label"])
+			if _, ok := definitions[key]; !ok {
+				definitions[key] = el
+			}
+		}
+		return WalkContinue
+	})
+	return definitions
+}
+
+// Footnotes resolves footnote references of document to their definitions.
+// Footnotes are numbered from 1 in order of their first reference.
+// Definitions without reference are left out.
+func (d *Document) Footnotes() []*Footnote {
+	return resolveFootnotes(d.Element)
+}
+
+func resolveFootnotes(root *Element) []*Footnote {
+	definitions := footnoteDefinitions(root)
+	footnotes := []*Footnote{}
+	byKey := map[string]*Footnote{}
+
+	Walk(root, func(el *Element, entering bool) WalkStatus {
+		if !entering || el.Kind() != KindFootnoteReference {
+			return WalkContinue
+		}
+
+		key := footnoteKey(el.Attributes["label"])
+		footnote, ok := byKey[key]
+		if !ok {
+			definition, ok := definitions[key]
+			if !ok {
+				return WalkContinue
+			}
+			footnote = &Footnote{
+				Label:      definition.Attributes["label"],
+				Number:     len(footnotes) + 1,
+				Definition: definition,
+				References: []*Element{},
+			}
+			footnotes = append(footnotes, footnote)
+			byKey[key] = footnote
+		}
+		footnote.References = append(footnote.References, el)
+		return WalkContinue
+	})
+
+	return footnotes
+}
+
+// numberFootnotes sets number attribute on referenced definitions and on their references,
+// which also get index among references to the same footnote
+func numberFootnotes(root *Element) {
+	for _, footnote := range resolveFootnotes(root) {
+		number := strconv.Itoa(footnote.Number)
+		footnote.Definition.Attributes["number"] = number
+		for i, ref := range footnote.References {
+			ref.Attributes["number"] = number
+			ref.Attributes["index"] = strconv.Itoa(i + 1)
+		}
+	}
+}
+
+// footnoteReferenceID returns HTML id of index-th reference to footnote number
+func footnoteReferenceID(number, index string) string {
+	if index == "" || index == "1" {
+		return "fnref-" + number
+	}
+	return "fnref-" + number + "-" + index
+}
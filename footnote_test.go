@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTryFootnoteDefinition(t *testing.T) {
+	label, content, ok := tryFootnoteDefinition("[^note]: first\n    second\n\n\tthird")
+
+	assert.True(t, ok)
+	assert.Equal(t, "note", label)
+	assert.Equal(t, "first\nsecond\n\nthird", content)
+}
+
+func TestTryFootnoteDefinitionFailed(t *testing.T) {
+	for _, block := range []string{"[^]: empty", "[^a b]: space", "[note]: link", "    [^1]: indented"} {
+		_, _, ok := tryFootnoteDefinition(block)
+
+		assert.False(t, ok, block)
+	}
+}
+
+func TestNewFootnote(t *testing.T) {
+	result := NewFootnote("1", "first\n\nsecond")
+
+	assert.Equal(t, "footnote", result.Type)
+	assert.Equal(t, "1", result.FootnoteLabel())
+	assert.Equal(t, 2, len(result.Elements))
+	assert.Equal(t, "second", result.Elements[1].Text)
+}
+
+func TestParseFootnoteReference(t *testing.T) {
+	doc := Parse("Text[^Note] and [^missing].\n\n[^note]: Defined.")
+
+	paragraph := doc.Elements[0]
+	assert.Equal(t, 3, len(paragraph.Elements))
+	ref := paragraph.Elements[1]
+	assert.Equal(t, "footnote-ref", ref.Type)
+	assert.Equal(t, map[string]string{"label": "Note", "number": "1", "index": "1"}, ref.Attributes)
+	assert.Equal(t, Span{Start: Position{Line: 1, Column: 5, Offset: 4}, End: Position{Line: 1, Column: 12, Offset: 11}}, ref.Span)
+	assert.Equal(t, " and [^missing].", paragraph.Elements[2].Text)
+}
+
+func TestDocumentFootnotes(t *testing.T) {
+	doc := Parse("A[^b] B[^a] C[^b]\n\n[^a]: Alpha\n[^b]: Beta\n[^c]: Unused")
+
+	footnotes := doc.Footnotes()
+
+	assert.Equal(t, 2, len(footnotes))
+	assert.Equal(t, "b", footnotes[0].Label)
+	assert.Equal(t, 1, footnotes[0].Number)
+	assert.Equal(t, 2, len(footnotes[0].References))
+	assert.Equal(t, "Beta", footnotes[0].Definition.Elements[0].Text)
+	assert.Equal(t, "a", footnotes[1].Label)
+	assert.Equal(t, 2, footnotes[1].Number)
+	assert.Equal(t, 2, footnotes[1].Definition.FootnoteNumber())
+	assert.Equal(t, 0, doc.Elements[3].FootnoteNumber())
+}
+
+func TestParseDuplicateFootnoteDefinition(t *testing.T) {
+	doc := Parse("Text[^1]\n\n[^1]: First\n[^1]: Second")
+
+	assert.Equal(t, "First", doc.Footnotes()[0].Definition.Elements[0].Text)
+	assert.Equal(t, []Diagnostic{{Line: 4, Message: "duplicate footnote definition [^1]"}}, doc.Diagnostics)
+}
+
+func TestRenderMarkdownFootnotes(t *testing.T) {
+	content := "Text[^1]\n\n[^1]: First\n    lazy\n\n    Second\n"
+
+	result := renderMarkdown(t, Parse(content))
+
+	assert.Equal(t, content, result)
+}
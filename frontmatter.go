@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Front matter formats
+const (
+	FrontMatterYAML = "yaml"
+	FrontMatterTOML = "toml"
+	FrontMatterJSON = "json"
+)
+
+// FrontMatter is metadata block at the very start of document, delimited by
+// --- for YAML, +++ for TOML or written as JSON object
+type FrontMatter struct {
+	Format string
+	// Raw is text between delimiters, or the whole object for JSON
+	Raw string
+	// Data holds parsed values: strings, bools, ints, float64s, nil,
+	// []interface{} and map[string]interface{}, and time.Time for TOML dates.
+	// It is empty when front matter cannot be decoded.
+	Data map[string]interface{}
+}
+
+// frontMatterDelimiters maps opening line to format
+var frontMatterDelimiters = map[string]string{
+	"---": FrontMatterYAML,
+	"+++": FrontMatterTOML,
+}
+
+// splitFrontMatter separates front matter from the beginning of content.
+// It returns front matter, rest of content and number of lines taken by front matter.
+func (p *parser) splitFrontMatter(content string) (*FrontMatter, string, int) {
+	lines := strings.Split(content, "\n")
+	first := strings.TrimRight(lines[0], " \t\r")
+
+	if strings.HasPrefix(first, "{") {
+		return p.splitJSONFrontMatter(content)
+	}
+
+	format, ok := frontMatterDelimiters[first]
+	if !ok {
+		return nil, content, 0
+	}
+
+	for i := 1; i < len(lines); i++ {
+		closing := strings.TrimRight(lines[i], " \t\r")
+		if closing != first && !(format == FrontMatterYAML && closing == "...") {
+			continue
+		}
+
+		fm := &FrontMatter{
+			Format: format,
+			Raw:    strings.Join(lines[1:i], "\n"),
+		}
+		if format == FrontMatterYAML {
+			data, ok := p.parseYAML(fm.Raw, 2)
+			if !ok {
+				// thematic breaks around ordinary content, not front matter
+				return nil, content, 0
+			}
+			fm.Data = data
+		} else {
+			fm.Data = p.parseTOML(fm.Raw, 2)
+		}
+		return fm, strings.Join(lines[i+1:], "\n"), i + 1
+	}
+
+	// without closing delimiter it is not front matter
+	return nil, content, 0
+}
+
+// splitJSONFrontMatter separates JSON object ending a line from the beginning of content
+func (p *parser) splitJSONFrontMatter(content string) (*FrontMatter, string, int) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, content, 0
+	}
+
+	end := int(decoder.InputOffset())
+	rest := content[end:]
+	eol := strings.Index(rest, "\n")
+	if eol < 0 {
+		eol = len(rest)
+	}
+	if strings.TrimSpace(rest[:eol]) != "" {
+		return nil, content, 0
+	}
+
+	raw := content[:end]
+	lineCount := strings.Count(raw, "\n") + 1
+	if eol < len(rest) {
+		rest = rest[eol+1:]
+	} else {
+		rest = ""
+	}
+
+	return &FrontMatter{
+		Format: FrontMatterJSON,
+		Raw:    raw,
+		Data:   jsonValue(data).(map[string]interface{}),
+	}, rest, lineCount
+}
+
+// jsonValue converts decoded JSON numbers to int or float64 like YAML and TOML values
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonValue(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = jsonValue(v[k])
+		}
+	}
+	return value
+}
+
+// renderFrontMatter writes front matter with its delimiters
+func renderFrontMatter(fm *FrontMatter) string {
+	if fm.Format == FrontMatterJSON {
+		return strings.TrimSpace(fm.Raw)
+	}
+
+	delimiter := "---"
+	if fm.Format == FrontMatterTOML {
+		delimiter = "+++"
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString(delimiter + "\n")
+	if fm.Raw != "" {
+		buf.WriteString(fm.Raw + "\n")
+	}
+	buf.WriteString(delimiter)
+	return buf.String()
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYAMLFrontMatter(t *testing.T) {
+	result := Parse("---\ntitle: Hello\ntags: [go, markdown]\nweight: 10\n---\n# Heading")
+
+	assert.Equal(t, &FrontMatter{
+		Format: FrontMatterYAML,
+		Raw:    "title: Hello\ntags: [go, markdown]\nweight: 10",
+		Data: map[string]interface{}{
+			"title":  "Hello",
+			"tags":   []interface{}{"go", "markdown"},
+			"weight": 10,
+		},
+	}, result.FrontMatter)
+	assert.Equal(t, 1, len(result.Elements))
+	assert.Equal(t, "h1", result.Elements[0].Type)
+	assert.Equal(t, Position{Line: 6, Column: 1, Offset: 53}, result.Elements[0].Span.Start)
+}
+
+func TestParseTOMLFrontMatter(t *testing.T) {
+	result := Parse("+++\ntitle = \"Hello\"\nweight = 10\n+++\n\ntext")
+
+	assert.Equal(t, &FrontMatter{
+		Format: FrontMatterTOML,
+		Raw:    "title = \"Hello\"\nweight = 10",
+		Data: map[string]interface{}{
+			"title":  "Hello",
+			"weight": 10,
+		},
+	}, result.FrontMatter)
+	assert.Equal(t, 1, len(result.Elements))
+	assert.Equal(t, 6, result.Elements[0].Span.Start.Line)
+}
+
+func TestParseJSONFrontMatter(t *testing.T) {
+	result := Parse("{\n  \"title\": \"Hello\",\n  \"weight\": 1.5\n}\ntext")
+
+	assert.Equal(t, &FrontMatter{
+		Format: FrontMatterJSON,
+		Raw:    "{\n  \"title\": \"Hello\",\n  \"weight\": 1.5\n}",
+		Data: map[string]interface{}{
+			"title":  "Hello",
+			"weight": 1.5,
+		},
+	}, result.FrontMatter)
+	assert.Equal(t, "text", result.Elements[0].Text)
+	assert.Equal(t, 5, result.Elements[0].Span.Start.Line)
+}
+
+func TestParseWithoutFrontMatter(t *testing.T) {
+	for _, content := range []string{
+		"---\nnot closed",
+		"text\n---\ntitle: x\n---",
+		"{not json}",
+		"{\"a\": 1} trailing",
+	} {
+		result := Parse(content)
+
+		assert.Nil(t, result.FrontMatter, content)
+		assert.NotEmpty(t, result.Elements, content)
+	}
+}
+
+func TestParseThematicBreaksAroundContentAreNotFrontMatter(t *testing.T) {
+	result := Parse("---\n\nIntro paragraph.\n\n---\n\n# Title")
+
+	assert.Nil(t, result.FrontMatter)
+	assert.Empty(t, result.Diagnostics)
+	assert.Equal(t, []NodeKind{KindThematicBreak, KindParagraph, KindThematicBreak, KindHeading}, []NodeKind{
+		result.Elements[0].Kind(), result.Elements[1].Kind(), result.Elements[2].Kind(), result.Elements[3].Kind(),
+	})
+	assert.Equal(t, "Intro paragraph.", result.Elements[1].Text)
+}
+
+func TestParseFrontMatterDiagnostics(t *testing.T) {
+	result := Parse("---\ntitle: x\n  bad: y\n---\ntext")
+
+	assert.Equal(t, map[string]interface{}{}, result.FrontMatter.Data)
+	assert.Equal(t, []Diagnostic{{Line: 3, Message: "invalid front matter: mapping values are not allowed in this context"}}, result.Diagnostics)
+	assert.Equal(t, "text", result.Elements[0].Text)
+}
+
+func TestRenderMarkdownFrontMatter(t *testing.T) {
+	for _, content := range []string{
+		"---\ntitle: Hello\n---\n\n# Heading\n",
+		"+++\ntitle = \"Hello\"\n+++\n\ntext\n",
+		"{\"title\": \"Hello\"}\n\ntext\n",
+		"---\n---\n",
+	} {
+		assert.Equal(t, content, renderMarkdown(t, Parse(content)))
+	}
+}
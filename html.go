@@ -0,0 +1,357 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// htmlEscaper escapes text for HTML content and attribute values
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;",
+)
+
+// HTMLOption configures HTML rendering
+type HTMLOption func(*htmlRenderer)
+
+// HTMLHardWraps renders line breaks inside paragraphs as <br />
+func HTMLHardWraps() HTMLOption {
+	return func(r *htmlRenderer) {
+		r.hardWraps = true
+	}
+}
+
+// HTMLTOCLevels limits headings listed at table of contents placeholder to
+// levels minLevel to maxLevel. All levels are listed by default.
+func HTMLTOCLevels(minLevel, maxLevel int) HTMLOption {
+	return func(r *htmlRenderer) {
+		r.tocMinLevel = minLevel
+		r.tocMaxLevel = maxLevel
+	}
+}
+
+type htmlRenderer struct {
+	buf          bytes.Buffer
+	doc          *Document
+	hardWraps    bool
+	tocMinLevel  int
+	tocMaxLevel  int
+	footnotes    []*Footnote
+	footnoteRefs map[*Element][2]int
+}
+
+// RenderHTML renders document as HTML to writer
+func RenderHTML(doc *Document, w io.Writer, opts ...HTMLOption) error {
+	r := &htmlRenderer{
+		doc:         doc,
+		tocMinLevel: 1,
+		tocMaxLevel: 6,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.footnotes = doc.Footnotes()
+	r.footnoteRefs = map[*Element][2]int{}
+	for _, footnote := range r.footnotes {
+		for i, ref := range footnote.References {
+			r.footnoteRefs[ref] = [2]int{footnote.Number, i + 1}
+		}
+	}
+
+	r.renderElement(doc.Element)
+	r.renderFootnotes()
+
+	_, err := w.Write(r.buf.Bytes())
+	return err
+}
+
+func (r *htmlRenderer) renderElement(el *Element) {
+	switch el.Kind() {
+	case KindHeading:
+		if id := el.Attributes["id"]; id != "" {
+			r.buf.WriteString("<" + el.Type + " id=\"" + escapeHTML(id) + "\">")
+		} else {
+			r.buf.WriteString("<" + el.Type + ">")
+		}
+		r.renderInlineContent(el)
+		r.buf.WriteString("</" + el.Type + ">\n")
+		r.renderChildren(el)
+	case KindParagraph:
+		if isInTightList(el) {
+			r.renderInlineContent(el)
+			r.buf.WriteString("\n")
+		} else {
+			r.buf.WriteString("<p>")
+			r.renderInlineContent(el)
+			r.buf.WriteString("</p>\n")
+		}
+		r.renderChildren(el)
+	case KindCode:
+		if language, ok := el.Attributes["language"]; ok {
+			r.buf.WriteString("<pre><code class=\"language-" + escapeHTML(language) + "\">")
+		} else {
+			r.buf.WriteString("<pre><code>")
+		}
+		if el.Text != "" {
+			r.buf.WriteString(escapeHTML(el.Text) + "\n")
+		}
+		r.buf.WriteString("</code></pre>\n")
+	case KindTable:
+		r.renderTable(el)
+	case KindUnorderedList:
+		r.buf.WriteString("<ul>\n")
+		r.renderChildren(el)
+		r.buf.WriteString("</ul>\n")
+	case KindOrderedList:
+		if start, ok := el.Attributes["start"]; ok && start != "1" {
+			r.buf.WriteString("<ol start=\"" + start + "\">\n")
+		} else {
+			r.buf.WriteString("<ol>\n")
+		}
+		r.renderChildren(el)
+		r.buf.WriteString("</ol>\n")
+	case KindBlockquote:
+		r.buf.WriteString("<blockquote>\n")
+		r.renderChildren(el)
+		r.buf.WriteString("</blockquote>\n")
+	case KindListItem:
+		r.renderListItem(el)
+	case KindThematicBreak:
+		r.buf.WriteString("<hr />\n")
+	case KindTOC:
+		writeTOCHTML(&r.buf, r.doc.TOC(r.tocMinLevel, r.tocMaxLevel))
+	case KindFootnote:
+		// footnotes are rendered at the end of document
+	case KindHTML:
+		r.buf.WriteString(el.Text + "\n")
+	default:
+		r.renderChildren(el)
+	}
+}
+
+// renderChildren writes block children of element
+func (r *htmlRenderer) renderChildren(el *Element) {
+	for _, child := range el.Elements {
+		if !child.Inline {
+			r.renderElement(child)
+		}
+	}
+}
+
+// renderInlineContent writes inline children of element, or its raw text when it has none
+func (r *htmlRenderer) renderInlineContent(el *Element) {
+	rendered := false
+	for _, child := range el.Elements {
+		if child.Inline {
+			r.renderInline(child)
+			rendered = true
+		}
+	}
+	if !rendered {
+		r.renderText(el.Text)
+	}
+}
+
+func (r *htmlRenderer) renderInline(el *Element) {
+	switch el.Kind() {
+	case KindText:
+		r.renderText(el.Text)
+	case KindEmphasis:
+		r.buf.WriteString("<em>")
+		r.renderInlineChildren(el)
+		r.buf.WriteString("</em>")
+	case KindStrong:
+		r.buf.WriteString("<strong>")
+		r.renderInlineChildren(el)
+		r.buf.WriteString("</strong>")
+	case KindCodeSpan:
+		r.buf.WriteString("<code>")
+		r.buf.WriteString(escapeHTML(el.Text))
+		r.buf.WriteString("</code>")
+	case KindLink:
+		r.buf.WriteString("<a href=\"" + escapeHTML(el.Attributes["href"]) + "\"")
+		r.renderTitle(el)
+		r.buf.WriteString(">")
+		r.renderInlineChildren(el)
+		r.buf.WriteString("</a>")
+	case KindImage:
+		r.buf.WriteString("<img src=\"" + escapeHTML(el.Attributes["src"]) + "\" alt=\"" + escapeHTML(el.Attributes["alt"]) + "\"")
+		r.renderTitle(el)
+		r.buf.WriteString(" />")
+	case KindFootnoteReference:
+		ref, ok := r.footnoteRefs[el]
+		if !ok {
+			r.renderText("[^" + el.Attributes["label"] + "]")
+			return
+		}
+		number := strconv.Itoa(ref[0])
+		id := footnoteReferenceID(number, strconv.Itoa(ref[1]))
+		r.buf.WriteString("<sup class=\"footnote-ref\"><a href=\"#fn-" + number + "\" id=\"" + id + "\">" + number + "</a></sup>")
+	default:
+		r.renderInlineChildren(el)
+	}
+}
+
+func (r *htmlRenderer) renderInlineChildren(el *Element) {
+	for _, child := range el.Elements {
+		r.renderInline(child)
+	}
+}
+
+func (r *htmlRenderer) renderTitle(el *Element) {
+	if title, ok := el.Attributes["title"]; ok {
+		r.buf.WriteString(" title=\"" + escapeHTML(title) + "\"")
+	}
+}
+
+// renderText writes escaped text, honoring hard wraps option
+func (r *htmlRenderer) renderText(text string) {
+	text = escapeHTML(text)
+	if r.hardWraps {
+		text = strings.Replace(text, "\n", "<br />\n", -1)
+	}
+	r.buf.WriteString(text)
+}
+
+// renderListItem writes item text, wrapped in paragraph for loose list, followed by nested blocks
+func (r *htmlRenderer) renderListItem(el *Element) {
+	tight := el.Parent == nil || el.Parent.Attributes["tight"] != "false"
+	hasBlocks := len(el.Elements) > 0 && !el.Elements[len(el.Elements)-1].Inline
+
+	r.buf.WriteString("<li>")
+	if tight {
+		r.renderTaskCheckbox(el)
+		r.renderInlineContent(el)
+		if hasBlocks {
+			r.buf.WriteString("\n")
+		}
+	} else {
+		r.buf.WriteString("\n")
+		if el.Text != "" {
+			r.buf.WriteString("<p>")
+			r.renderTaskCheckbox(el)
+			r.renderInlineContent(el)
+			r.buf.WriteString("</p>\n")
+		}
+	}
+	r.renderChildren(el)
+	r.buf.WriteString("</li>\n")
+}
+
+// renderTaskCheckbox writes disabled checkbox of task list item
+func (r *htmlRenderer) renderTaskCheckbox(el *Element) {
+	if !el.ListTask() {
+		return
+	}
+	if el.TaskChecked() {
+		r.buf.WriteString(`<input type="checkbox" checked="" disabled="" /> `)
+	} else {
+		r.buf.WriteString(`<input type="checkbox" disabled="" /> `)
+	}
+}
+
+// renderFootnotes writes section of referenced footnotes in order of their numbers.
+// Links back to references are added to the last paragraph of each footnote.
+func (r *htmlRenderer) renderFootnotes() {
+	if len(r.footnotes) == 0 {
+		return
+	}
+
+	r.buf.WriteString("<section class=\"footnotes\">\n<ol>\n")
+	for _, footnote := range r.footnotes {
+		r.buf.WriteString("<li id=\"fn-" + strconv.Itoa(footnote.Number) + "\">\n")
+
+		blocks := []*Element{}
+		for _, child := range footnote.Definition.Elements {
+			if !child.Inline {
+				blocks = append(blocks, child)
+			}
+		}
+
+		n := len(blocks)
+		if n > 0 && blocks[n-1].Kind() == KindParagraph {
+			for _, block := range blocks[:n-1] {
+				r.renderElement(block)
+			}
+			r.buf.WriteString("<p>")
+			r.renderInlineContent(blocks[n-1])
+			r.buf.WriteString(" " + footnoteBackReferences(footnote) + "</p>\n")
+		} else {
+			for _, block := range blocks {
+				r.renderElement(block)
+			}
+			r.buf.WriteString("<p>" + footnoteBackReferences(footnote) + "</p>\n")
+		}
+
+		r.buf.WriteString("</li>\n")
+	}
+	r.buf.WriteString("</ol>\n</section>\n")
+}
+
+// footnoteBackReferences returns links back to each reference of footnote
+func footnoteBackReferences(footnote *Footnote) string {
+	number := strconv.Itoa(footnote.Number)
+	links := []string{}
+	for i := range footnote.References {
+		index := strconv.Itoa(i + 1)
+		link := "<a href=\"#" + footnoteReferenceID(number, index) + "\" class=\"footnote-backref\">↩"
+		if i > 0 {
+			link += "<sup>" + index + "</sup>"
+		}
+		links = append(links, link+"</a>")
+	}
+	return strings.Join(links, " ")
+}
+
+// renderTable writes first row as table header and the rest as table body
+func (r *htmlRenderer) renderTable(el *Element) {
+	r.buf.WriteString("<table>\n")
+	for i, row := range el.Elements {
+		if i == 0 {
+			r.buf.WriteString("<thead>\n")
+			r.renderRow(row, "th")
+			r.buf.WriteString("</thead>\n")
+			continue
+		}
+		if i == 1 {
+			r.buf.WriteString("<tbody>\n")
+		}
+		r.renderRow(row, "td")
+	}
+	if len(el.Elements) > 1 {
+		r.buf.WriteString("</tbody>\n")
+	}
+	r.buf.WriteString("</table>\n")
+}
+
+func (r *htmlRenderer) renderRow(row *Element, tag string) {
+	r.buf.WriteString("<tr>\n")
+	for _, cell := range row.Elements {
+		if align, ok := cell.Attributes["align"]; ok {
+			r.buf.WriteString("<" + tag + " align=\"" + align + "\">")
+		} else {
+			r.buf.WriteString("<" + tag + ">")
+		}
+		r.renderInlineContent(cell)
+		r.buf.WriteString("</" + tag + ">\n")
+	}
+	r.buf.WriteString("</tr>\n")
+}
+
+func escapeHTML(text string) string {
+	return htmlEscaper.Replace(text)
+}
+
+// isInTightList checks whether paragraph belongs to item of tight list
+func isInTightList(el *Element) bool {
+	item := el.Parent
+	if item == nil || item.Type != "list-item" || item.Parent == nil {
+		return false
+	}
+	return item.Parent.Attributes["tight"] == "true"
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+func TestRenderHTMLGoldenFiles(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "html", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+		golden := strings.TrimSuffix(file, ".md") + ".html"
+
+		t.Run(name, func(t *testing.T) {
+			content, err := ioutil.ReadFile(file)
+			assert.NoError(t, err)
+
+			var out bytes.Buffer
+			err = RenderHTML(Parse(string(content)), &out)
+			assert.NoError(t, err)
+
+			if *updateGolden {
+				assert.NoError(t, ioutil.WriteFile(golden, out.Bytes(), 0644))
+			}
+
+			expected, err := ioutil.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), out.String())
+		})
+	}
+}
+
+func TestRenderHTMLEscapesText(t *testing.T) {
+	doc := NewDocument()
+	doc.Append(NewElement("h1", "Fish & <Chips>"))
+	doc.Append(NewElement("text", "\"quoted\" & 'single'"))
+
+	var out bytes.Buffer
+	err := RenderHTML(doc, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<h1>Fish &amp; &lt;Chips&gt;</h1>\n<p>&quot;quoted&quot; &amp; 'single'</p>\n", out.String())
+}
+
+func TestRenderHTMLWithHardWraps(t *testing.T) {
+	var out bytes.Buffer
+	err := RenderHTML(Parse("Test\nTest2"), &out, HTMLHardWraps())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Test<br />\nTest2</p>\n", out.String())
+}
+
+func TestRenderHTMLTableWithoutBody(t *testing.T) {
+	doc := NewDocument()
+	doc.Append(NewTable([][]string{
+		[]string{"Header"},
+	}))
+
+	var out bytes.Buffer
+	err := RenderHTML(doc, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<table>\n<thead>\n<tr>\n<th>Header</th>\n</tr>\n</thead>\n</table>\n", out.String())
+}
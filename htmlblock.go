@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// htmlBlockTags lists tag names starting HTML block of kind 6
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "base": true, "basefont": true,
+	"blockquote": true, "body": true, "caption": true, "center": true, "col": true,
+	"colgroup": true, "dd": true, "details": true, "dialog": true, "dir": true,
+	"div": true, "dl": true, "dt": true, "fieldset": true, "figcaption": true,
+	"figure": true, "footer": true, "form": true, "frame": true, "frameset": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"head": true, "header": true, "hr": true, "html": true, "iframe": true,
+	"legend": true, "li": true, "link": true, "main": true, "menu": true,
+	"menuitem": true, "nav": true, "noframes": true, "ol": true, "optgroup": true,
+	"option": true, "p": true, "param": true, "search": true, "section": true,
+	"summary": true, "table": true, "tbody": true, "td": true, "tfoot": true,
+	"th": true, "thead": true, "title": true, "tr": true, "track": true, "ul": true,
+}
+
+// htmlBlockStarts holds start conditions of HTML block kinds 1 to 5
+var htmlBlockStarts = []*regexp.Regexp{
+	regexp.MustCompile(`^ {0,3}<(?i:pre|script|style|textarea)(?:[ \t>]|$)`),
+	regexp.MustCompile(`^ {0,3}<!--`),
+	regexp.MustCompile(`^ {0,3}<\?`),
+	regexp.MustCompile(`^ {0,3}<![A-Za-z]`),
+	regexp.MustCompile(`^ {0,3}<!\[CDATA\[`),
+}
+
+// htmlBlockEnds holds end conditions of HTML block kinds 1 to 5
+var htmlBlockEnds = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)</(?:pre|script|style|textarea)>`),
+	regexp.MustCompile(`-->`),
+	regexp.MustCompile(`\?>`),
+	regexp.MustCompile(`>`),
+	regexp.MustCompile(`\]\]>`),
+}
+
+// htmlBlockTagPattern matches opening or closing tag of HTML block kind 6
+var htmlBlockTagPattern = regexp.MustCompile(`^ {0,3}</?([A-Za-z][A-Za-z0-9-]*)(?:[ \t>]|/>|$)`)
+
+// htmlTagPattern matches line holding only a complete opening or closing tag, HTML block kind 7
+var htmlTagPattern = regexp.MustCompile(`^ {0,3}(?:<([A-Za-z][A-Za-z0-9-]*)` +
+	`(?:[ \t]+[A-Za-z_:][A-Za-z0-9_.:-]*(?:[ \t]*=[ \t]*(?:[^ \t"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*` +
+	`[ \t]*/?>|</([A-Za-z][A-Za-z0-9-]*)[ \t]*>)[ \t]*$`)
+
+// htmlBlockStart returns kind 1 to 7 of CommonMark HTML block started by line, or 0 when there is none
+func htmlBlockStart(line string) int {
+	for i, re := range htmlBlockStarts {
+		if re.MatchString(line) {
+			return i + 1
+		}
+	}
+	if m := htmlBlockTagPattern.FindStringSubmatch(line); m != nil && htmlBlockTags[strings.ToLower(m[1])] {
+		return 6
+	}
+	if m := htmlTagPattern.FindStringSubmatch(line); m != nil {
+		switch strings.ToLower(m[1] + m[2]) {
+		case "pre", "script", "style", "textarea":
+			return 0
+		}
+		return 7
+	}
+	return 0
+}
+
+// isHTMLBlockEnd checks whether line ends HTML block of kind.
+// Blocks of kind 6 and 7 end at blank line instead.
+func isHTMLBlockEnd(kind int, line string) bool {
+	if kind < 1 || kind > len(htmlBlockEnds) {
+		return false
+	}
+	return htmlBlockEnds[kind-1].MatchString(line)
+}
+
+// isHTMLBlock checks whether block is raw HTML
+func isHTMLBlock(block string) bool {
+	return htmlBlockStart(strings.SplitN(block, "\n", 2)[0]) != 0
+}
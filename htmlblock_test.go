@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLBlockStart(t *testing.T) {
+	lines := map[string]int{
+		"<pre>":                1,
+		"<SCRIPT src=x>":       1,
+		"<!-- comment":         2,
+		"<?php":                3,
+		"<!DOCTYPE html>":      4,
+		"<![CDATA[":            5,
+		"<div class=\"a\">":    6,
+		"</table>":             6,
+		"   <p/>":              6,
+		"<span>":               7,
+		"<a href='x' title=y>": 7,
+		"</custom-tag>  ":      7,
+		"<span>text</span>":    0,
+		"<prefix>":             7,
+		"    <div>":            0,
+		"<a href=x":            0,
+		"text <div>":           0,
+	}
+
+	for line, kind := range lines {
+		assert.Equal(t, kind, htmlBlockStart(line), line)
+	}
+}
+
+func TestIsHTMLBlockEnd(t *testing.T) {
+	assert.True(t, isHTMLBlockEnd(1, "code</PRE>"))
+	assert.True(t, isHTMLBlockEnd(2, "end -->"))
+	assert.False(t, isHTMLBlockEnd(2, "end ->"))
+	assert.True(t, isHTMLBlockEnd(4, "html>"))
+	assert.False(t, isHTMLBlockEnd(6, "</div>"))
+}
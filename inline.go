@@ -0,0 +1,669 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// inlineContainers lists block types whose text holds inline markup
+var inlineContainers = map[string]bool{
+	"text":      true,
+	"h1":        true,
+	"h2":        true,
+	"h3":        true,
+	"h4":        true,
+	"h5":        true,
+	"h6":        true,
+	"list-item": true,
+	"cell":      true,
+}
+
+// inlineNode is a node in the working list of inline elements
+type inlineNode struct {
+	el   *Element
+	prev *inlineNode
+	next *inlineNode
+}
+
+// delimiter is an entry of emphasis delimiter stack
+type delimiter struct {
+	node      *inlineNode
+	char      byte
+	count     int
+	origCount int
+	canOpen   bool
+	canClose  bool
+	prev      *delimiter
+	next      *delimiter
+}
+
+// bracket is an entry of link and image opener stack
+type bracket struct {
+	node   *inlineNode
+	image  bool
+	active bool
+	delims *delimiter
+	prev   *bracket
+}
+
+// inlineParser holds state of parsing inline markup of a single text.
+// Spans keep start and end offset in text of each created element.
+type inlineParser struct {
+	text      string
+	pos       int
+	head      *inlineNode
+	tail      *inlineNode
+	delims    *delimiter
+	brackets  *bracket
+	spans     map[*Element][2]int
+	footnotes map[string]*Element
+	links     map[string]*LinkReference
+}
+
+// parseInlines converts text of inline containers in element tree into inline child elements
+func (p *parser) parseInlines(el *Element) {
+	for _, child := range el.Elements {
+		p.parseInlines(child)
+	}
+
+	if el.Inline || !inlineContainers[el.Type] {
+		return
+	}
+
+	ip := newInlineParser(el.Text, p.footnotes, p.linkReferences)
+	nodes := ip.collect(ip.head, nil)
+	if !el.Span.Start.IsZero() {
+		starts := p.textLineStarts(el)
+		for _, node := range nodes {
+			ip.locate(node, starts)
+		}
+	}
+	for _, node := range nodes {
+		node.Parent = el
+	}
+	el.Elements = append(nodes, el.Elements...)
+}
+
+// parseInline parses inline markup in text following CommonMark rules
+func parseInline(text string) []*Element {
+	p := newInlineParser(text, nil, nil)
+	return p.collect(p.head, nil)
+}
+
+// newInlineParser parses text, resolving references to footnotes and links defined by labels
+func newInlineParser(text string, footnotes map[string]*Element, links map[string]*LinkReference) *inlineParser {
+	p := &inlineParser{
+		text:      text,
+		spans:     map[*Element][2]int{},
+		footnotes: footnotes,
+		links:     links,
+	}
+	p.parse()
+	return p
+}
+
+// locate sets source span of element and its children from their offsets in text
+func (p *inlineParser) locate(el *Element, starts []Position) {
+	if offsets, ok := p.spans[el]; ok {
+		el.Span = Span{
+			Start: textPosition(p.text, offsets[0], starts),
+			End:   textPosition(p.text, offsets[1], starts),
+		}
+	}
+	for _, child := range el.Elements {
+		p.locate(child, starts)
+	}
+}
+
+func (p *inlineParser) parse() {
+	literal := strings.Builder{}
+	literalStart := 0
+	flush := func() {
+		if literal.Len() > 0 {
+			p.appendText(literal.String(), literalStart, p.pos)
+			literal.Reset()
+		}
+	}
+
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if literal.Len() == 0 {
+			literalStart = p.pos
+		}
+		switch {
+		case c == '\\' && p.pos+1 < len(p.text) && isASCIIPunct(p.text[p.pos+1]):
+			literal.WriteByte(p.text[p.pos+1])
+			p.pos += 2
+		case c == '`':
+			flush()
+			p.parseCodeSpan()
+		case c == '*' || c == '_':
+			flush()
+			p.parseDelimiterRun(c)
+		case c == '[' && p.footnoteReferenceAt(p.pos) != "":
+			flush()
+			p.parseFootnoteReference()
+		case c == '!' && p.pos+1 < len(p.text) && p.text[p.pos+1] == '[':
+			flush()
+			p.pushBracket(p.appendText("![", p.pos, p.pos+2), true)
+			p.pos += 2
+		case c == '[':
+			flush()
+			p.pushBracket(p.appendText("[", p.pos, p.pos+1), false)
+			p.pos++
+		case c == ']':
+			flush()
+			p.pos++
+			p.closeBracket()
+		default:
+			literal.WriteByte(c)
+			p.pos++
+		}
+	}
+	flush()
+
+	p.processEmphasis(nil)
+}
+
+// parseCodeSpan parses backtick string at current position
+func (p *inlineParser) parseCodeSpan() {
+	start := p.pos
+	ticks := countRun(p.text, p.pos, '`')
+	p.pos += ticks
+
+	for i := p.pos; i < len(p.text); {
+		if p.text[i] != '`' {
+			i++
+			continue
+		}
+		n := countRun(p.text, i, '`')
+		if n == ticks {
+			content := strings.Replace(p.text[p.pos:i], "\n", " ", -1)
+			if len(content) > 1 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.Trim(content, " ") != "" {
+				content = content[1 : len(content)-1]
+			}
+			p.pos = i + n
+			p.appendNode(newInlineElement("code-span", content), start, p.pos)
+			return
+		}
+		i += n
+	}
+
+	// no matching closer, backticks are literal
+	p.appendText(p.text[start:p.pos], start, p.pos)
+}
+
+// parseDelimiterRun pushes run of * or _ onto delimiter stack
+func (p *inlineParser) parseDelimiterRun(c byte) {
+	start := p.pos
+	count := countRun(p.text, p.pos, c)
+	p.pos += count
+
+	before, _ := utf8.DecodeLastRuneInString(p.text[:start])
+	after, _ := utf8.DecodeRuneInString(p.text[p.pos:])
+	if start == 0 {
+		before = ' '
+	}
+	if p.pos == len(p.text) {
+		after = ' '
+	}
+
+	beforeSpace, afterSpace := unicode.IsSpace(before), unicode.IsSpace(after)
+	beforePunct, afterPunct := isPunct(before), isPunct(after)
+
+	leftFlanking := !afterSpace && (!afterPunct || beforeSpace || beforePunct)
+	rightFlanking := !beforeSpace && (!beforePunct || afterSpace || afterPunct)
+
+	canOpen, canClose := leftFlanking, rightFlanking
+	if c == '_' {
+		canOpen = leftFlanking && (!rightFlanking || beforePunct)
+		canClose = rightFlanking && (!leftFlanking || afterPunct)
+	}
+
+	node := p.appendText(p.text[start:p.pos], start, p.pos)
+	d := &delimiter{
+		node:      node,
+		char:      c,
+		count:     count,
+		origCount: count,
+		canOpen:   canOpen,
+		canClose:  canClose,
+		prev:      p.delims,
+	}
+	if p.delims != nil {
+		p.delims.next = d
+	}
+	p.delims = d
+}
+
+// footnoteReferencePattern matches [^label] footnote reference
+var footnoteReferencePattern = regexp.MustCompile(`^\[\^([^\]\s]+)\]`)
+
+// footnoteReferenceAt returns [^label] found at pos when label has footnote definition
+func (p *inlineParser) footnoteReferenceAt(pos int) string {
+	m := footnoteReferencePattern.FindStringSubmatch(p.text[pos:])
+	if m == nil {
+		return ""
+	}
+	if _, ok := p.footnotes[footnoteKey(m[1])]; !ok {
+		return ""
+	}
+	return m[0]
+}
+
+// parseFootnoteReference parses [^label] at current position
+func (p *inlineParser) parseFootnoteReference() {
+	ref := p.footnoteReferenceAt(p.pos)
+	el := newInlineElement("footnote-ref", "")
+	el.Attributes = map[string]string{
+		"label": ref[2 : len(ref)-1],
+	}
+	p.appendNode(el, p.pos, p.pos+len(ref))
+	p.pos += len(ref)
+}
+
+func (p *inlineParser) pushBracket(node *inlineNode, image bool) {
+	p.brackets = &bracket{
+		node:   node,
+		image:  image,
+		active: true,
+		delims: p.delims,
+		prev:   p.brackets,
+	}
+}
+
+// closeBracket tries to match ] with the nearest opener and build link or image
+func (p *inlineParser) closeBracket() {
+	opener := p.brackets
+	if opener == nil {
+		p.appendText("]", p.pos-1, p.pos)
+		return
+	}
+	if !opener.active {
+		p.brackets = opener.prev
+		p.appendText("]", p.pos-1, p.pos)
+		return
+	}
+
+	dest, title, end, ok := parseLinkTail(p.text, p.pos)
+	if !ok {
+		dest, title, end, ok = p.parseLinkReference(opener)
+	}
+	if !ok {
+		p.brackets = opener.prev
+		p.appendText("]", p.pos-1, p.pos)
+		return
+	}
+	p.pos = end
+
+	p.processEmphasis(opener.delims)
+
+	var el *Element
+	children := p.collect(opener.node.next, nil)
+	if opener.image {
+		el = newInlineElement("image", "")
+		el.Attributes = map[string]string{
+			"src": dest,
+			"alt": plainText(children),
+		}
+	} else {
+		el = newInlineElement("link", "")
+		el.Attributes = map[string]string{
+			"href": dest,
+		}
+		for _, child := range children {
+			child.Parent = el
+		}
+		el.Elements = children
+	}
+	if title != "" {
+		el.Attributes["title"] = title
+	}
+
+	// replace opener and its content with link node
+	start := p.spans[opener.node.el][0]
+	p.tail = opener.node.prev
+	if p.tail == nil {
+		p.head = nil
+	} else {
+		p.tail.next = nil
+	}
+	p.appendNode(el, start, p.pos)
+	p.brackets = opener.prev
+
+	// links may not contain other links
+	if !opener.image {
+		for b := p.brackets; b != nil; b = b.prev {
+			if !b.image {
+				b.active = false
+			}
+		}
+	}
+}
+
+// parseLinkReference resolves full [text][label], collapsed [text][] or shortcut [text]
+// reference link closed by ] before current position
+func (p *inlineParser) parseLinkReference(opener *bracket) (string, string, int, bool) {
+	label := p.text[p.spans[opener.node.el][1] : p.pos-1]
+	end := p.pos
+	if n := linkLabelLength(p.text, p.pos); n > 2 {
+		label = p.text[p.pos+1 : p.pos+n-1]
+		end = p.pos + n
+	} else if n == 2 {
+		end = p.pos + 2
+	}
+
+	ref, ok := p.links[normalizeLinkLabel(label)]
+	if !ok {
+		return "", "", 0, false
+	}
+	return ref.Destination, ref.Title, end, true
+}
+
+// processEmphasis resolves emphasis delimiters above stackBottom
+func (p *inlineParser) processEmphasis(stackBottom *delimiter) {
+	openersBottom := map[[3]int]*delimiter{}
+
+	var closer *delimiter
+	for d := p.delims; d != nil && d != stackBottom; d = d.prev {
+		closer = d
+	}
+
+	for closer != nil {
+		if !closer.canClose {
+			closer = closer.next
+			continue
+		}
+
+		key := [3]int{int(closer.char), boolToInt(closer.canOpen), closer.origCount % 3}
+		bottom, hasBottom := openersBottom[key]
+		if !hasBottom {
+			bottom = stackBottom
+		}
+
+		opener := closer.prev
+		found := false
+		for opener != nil && opener != stackBottom && opener != bottom {
+			oddMatch := (opener.canClose || closer.canOpen) &&
+				(opener.origCount+closer.origCount)%3 == 0 &&
+				!(opener.origCount%3 == 0 && closer.origCount%3 == 0)
+			if opener.char == closer.char && opener.canOpen && !oddMatch {
+				found = true
+				break
+			}
+			opener = opener.prev
+		}
+
+		if !found {
+			openersBottom[key] = closer.prev
+			next := closer.next
+			if !closer.canOpen {
+				p.removeDelimiter(closer)
+			}
+			closer = next
+			continue
+		}
+
+		use := 1
+		elType := "emphasis"
+		if opener.count >= 2 && closer.count >= 2 {
+			use = 2
+			elType = "strong"
+		}
+		opener.count -= use
+		closer.count -= use
+		opener.node.el.Text = opener.node.el.Text[:opener.count]
+		closer.node.el.Text = closer.node.el.Text[:closer.count]
+
+		// opener gives up its last characters and closer its first
+		openerSpan, closerSpan := p.spans[opener.node.el], p.spans[closer.node.el]
+		openerSpan[1] -= use
+		closerSpan[0] += use
+		p.spans[opener.node.el], p.spans[closer.node.el] = openerSpan, closerSpan
+
+		el := newInlineElement(elType, "")
+		p.spans[el] = [2]int{openerSpan[1], closerSpan[0]}
+		el.Elements = p.collect(opener.node.next, closer.node)
+		for _, child := range el.Elements {
+			child.Parent = el
+		}
+		node := &inlineNode{el: el, prev: opener.node, next: closer.node}
+		opener.node.next = node
+		closer.node.prev = node
+
+		// delimiters between opener and closer are inside emphasis now
+		opener.next = closer
+		closer.prev = opener
+
+		if opener.count == 0 {
+			p.removeNode(opener.node)
+			p.removeDelimiter(opener)
+		}
+		if closer.count == 0 {
+			next := closer.next
+			p.removeNode(closer.node)
+			p.removeDelimiter(closer)
+			closer = next
+		}
+	}
+
+	for p.delims != nil && p.delims != stackBottom {
+		p.removeDelimiter(p.delims)
+	}
+}
+
+func (p *inlineParser) appendText(text string, start, end int) *inlineNode {
+	return p.appendNode(newInlineElement("text", text), start, end)
+}
+
+// appendNode adds element found between start and end offsets of text
+func (p *inlineParser) appendNode(el *Element, start, end int) *inlineNode {
+	p.spans[el] = [2]int{start, end}
+	node := &inlineNode{el: el, prev: p.tail}
+	if p.tail == nil {
+		p.head = node
+	} else {
+		p.tail.next = node
+	}
+	p.tail = node
+	return node
+}
+
+func (p *inlineParser) removeNode(node *inlineNode) {
+	if node.prev == nil {
+		p.head = node.next
+	} else {
+		node.prev.next = node.next
+	}
+	if node.next == nil {
+		p.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+}
+
+func (p *inlineParser) removeDelimiter(d *delimiter) {
+	if d.prev != nil {
+		d.prev.next = d.next
+	}
+	if d.next == nil {
+		p.delims = d.prev
+	} else {
+		d.next.prev = d.prev
+	}
+}
+
+// collect returns elements from node up to (not including) end, merging adjacent text
+func (p *inlineParser) collect(node, end *inlineNode) []*Element {
+	output := []*Element{}
+	for ; node != nil && node != end; node = node.next {
+		el := node.el
+		if el.Type == "text" {
+			if el.Text == "" {
+				continue
+			}
+			if n := len(output); n > 0 && output[n-1].Type == "text" {
+				merged := newInlineElement("text", output[n-1].Text+el.Text)
+				p.spans[merged] = [2]int{p.spans[output[n-1]][0], p.spans[el][1]}
+				output[n-1] = merged
+				continue
+			}
+		}
+		output = append(output, el)
+	}
+	return output
+}
+
+// parseLinkTail parses (destination "title") following ] at pos
+func parseLinkTail(text string, pos int) (string, string, int, bool) {
+	if pos >= len(text) || text[pos] != '(' {
+		return "", "", 0, false
+	}
+	pos = skipLinkSpace(text, pos+1)
+
+	dest, pos, ok := parseLinkDestination(text, pos)
+	if !ok {
+		return "", "", 0, false
+	}
+
+	title := ""
+	next := skipLinkSpace(text, pos)
+	if next < len(text) && next > pos && strings.IndexByte("\"'(", text[next]) >= 0 {
+		title, next, ok = parseLinkTitle(text, next)
+		if !ok {
+			return "", "", 0, false
+		}
+		next = skipLinkSpace(text, next)
+	}
+
+	if next >= len(text) || text[next] != ')' {
+		return "", "", 0, false
+	}
+
+	return unescapeMarkdown(dest), unescapeMarkdown(title), next + 1, true
+}
+
+// parseLinkDestination parses <destination> or destination with balanced parentheses at pos.
+// It returns destination with escapes left in and position following it.
+func parseLinkDestination(text string, pos int) (string, int, bool) {
+	if pos < len(text) && text[pos] == '<' {
+		end := strings.IndexAny(text[pos+1:], ">\n")
+		if end < 0 || text[pos+1+end] != '>' {
+			return "", 0, false
+		}
+		return text[pos+1 : pos+1+end], pos + end + 2, true
+	}
+
+	start, depth := pos, 0
+	for pos < len(text) {
+		c := text[pos]
+		if c == '\\' && pos+1 < len(text) && isASCIIPunct(text[pos+1]) {
+			pos += 2
+			continue
+		}
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			if depth == 0 {
+				break
+			}
+			depth--
+		} else if c <= ' ' {
+			break
+		}
+		pos++
+	}
+	if depth != 0 {
+		return "", 0, false
+	}
+	return text[start:pos], pos, true
+}
+
+// parseLinkTitle parses title in double quotes, single quotes or parentheses starting at pos.
+// It returns title with escapes left in and position following it.
+func parseLinkTitle(text string, pos int) (string, int, bool) {
+	closer := text[pos]
+	if closer == '(' {
+		closer = ')'
+	}
+	end := pos + 1
+	for end < len(text) && text[end] != closer {
+		if text[end] == '\\' && end+1 < len(text) {
+			end++
+		}
+		end++
+	}
+	if end >= len(text) {
+		return "", 0, false
+	}
+	return text[pos+1 : end], end + 1, true
+}
+
+func skipLinkSpace(text string, pos int) int {
+	for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t' || text[pos] == '\n') {
+		pos++
+	}
+	return pos
+}
+
+// unescapeMarkdown removes backslash escapes from text
+func unescapeMarkdown(text string) string {
+	if !strings.Contains(text, "\\") {
+		return text
+	}
+	output := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && isASCIIPunct(text[i+1]) {
+			i++
+		}
+		output.WriteByte(text[i])
+	}
+	return output.String()
+}
+
+// plainText returns text content of inline elements without markup
+func plainText(elements []*Element) string {
+	output := strings.Builder{}
+	for _, el := range elements {
+		switch el.Type {
+		case "image":
+			output.WriteString(el.Attributes["alt"])
+		case "text", "code-span":
+			output.WriteString(el.Text)
+		default:
+			output.WriteString(plainText(el.Elements))
+		}
+	}
+	return output.String()
+}
+
+func newInlineElement(elType, text string) *Element {
+	el := NewElement(elType, text)
+	el.Inline = true
+	return el
+}
+
+func countRun(text string, pos int, c byte) int {
+	n := 0
+	for pos+n < len(text) && text[pos+n] == c {
+		n++
+	}
+	return n
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
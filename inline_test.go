@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// describeInlines renders inline elements as compact s-expressions for comparison
+func describeInlines(elements []*Element) string {
+	output := []string{}
+	for _, el := range elements {
+		desc := el.Type
+		if el.Text != "" {
+			desc += " " + strings.Replace(el.Text, "\n", "\\n", -1)
+		}
+		keys := []string{}
+		for key := range el.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			desc += " " + key + "=" + el.Attributes[key]
+		}
+		if len(el.Elements) > 0 {
+			desc += " " + describeInlines(el.Elements)
+		}
+		output = append(output, "("+desc+")")
+	}
+	return strings.Join(output, "")
+}
+
+func TestParseInlinePlainText(t *testing.T) {
+	result := parseInline("plain text")
+
+	assert.Equal(t, []*Element{
+		&Element{
+			Text:     "plain text",
+			Type:     "text",
+			Inline:   true,
+			Elements: []*Element{},
+		},
+	}, result)
+}
+
+func TestParseInlineEmptyText(t *testing.T) {
+	result := parseInline("")
+
+	assert.Equal(t, []*Element{}, result)
+}
+
+func TestParseInlineEmphasisAndStrong(t *testing.T) {
+	cases := map[string]string{
+		"*foo bar*":          "(emphasis (text foo bar))",
+		"_foo bar_":          "(emphasis (text foo bar))",
+		"**foo bar**":        "(strong (text foo bar))",
+		"__foo bar__":        "(strong (text foo bar))",
+		"a * foo bar*":       "(text a * foo bar*)",
+		"foo*bar*":           "(text foo)(emphasis (text bar))",
+		"foo_bar_":           "(text foo_bar_)",
+		"*foo**bar**baz*":    "(emphasis (text foo)(strong (text bar))(text baz))",
+		"***strong emph***":  "(emphasis (strong (text strong emph)))",
+		"**foo*":             "(text *)(emphasis (text foo))",
+		"*foo**":             "(emphasis (text foo))(text *)",
+		"*(*foo*)*":          "(emphasis (text ()(emphasis (text foo))(text )))",
+		"\\*not emphasized*": "(text *not emphasized*)",
+	}
+
+	for content, expected := range cases {
+		assert.Equal(t, expected, describeInlines(parseInline(content)), content)
+	}
+}
+
+func TestParseInlineCodeSpan(t *testing.T) {
+	cases := map[string]string{
+		"`foo`":            "(code-span foo)",
+		"`` foo ` bar ``":  "(code-span foo ` bar)",
+		"` `` `":           "(code-span ``)",
+		"`foo\nbar`":       "(code-span foo bar)",
+		"`*not emphasis*`": "(code-span *not emphasis*)",
+		"```unmatched``":   "(text ```unmatched``)",
+		"a `b` c":          "(text a )(code-span b)(text  c)",
+	}
+
+	for content, expected := range cases {
+		assert.Equal(t, expected, describeInlines(parseInline(content)), content)
+	}
+}
+
+func TestParseInlineLink(t *testing.T) {
+	cases := map[string]string{
+		"[link](/uri)":            "(link href=/uri (text link))",
+		"[link](/uri \"title\")":  "(link href=/uri title=title (text link))",
+		"[link](</my uri>)":       "(link href=/my uri (text link))",
+		"[link](foo(and(bar)))":   "(link href=foo(and(bar)) (text link))",
+		"[*em* link](/uri)":       "(link href=/uri (emphasis (text em))(text  link))",
+		"[link]()":                "(link href= (text link))",
+		"[link] (/uri)":           "(text [link] (/uri))",
+		"[foo [bar](/uri)](/uri)": "(text [foo )(link href=/uri (text bar))(text ](/uri))",
+		"*[foo*](/uri)":           "(text *)(link href=/uri (text foo*))",
+		"[not a link]":            "(text [not a link])",
+		"[link](/url\\))":         "(link href=/url) (text link))",
+	}
+
+	for content, expected := range cases {
+		assert.Equal(t, expected, describeInlines(parseInline(content)), content)
+	}
+}
+
+func TestParseInlineImage(t *testing.T) {
+	cases := map[string]string{
+		"![foo](/url \"title\")":   "(image alt=foo src=/url title=title)",
+		"![foo *bar*](train.jpg)":  "(image alt=foo bar src=train.jpg)",
+		"![foo [bar](/url)](/img)": "(image alt=foo bar src=/img)",
+		"[![img](/i.png)](/link)":  "(link href=/link (image alt=img src=/i.png))",
+	}
+
+	for content, expected := range cases {
+		assert.Equal(t, expected, describeInlines(parseInline(content)), content)
+	}
+}
+
+func TestParseInlinesSetsParents(t *testing.T) {
+	doc := Parse("Some *emphasized* text")
+
+	paragraph := doc.Elements[0]
+	assert.Len(t, paragraph.Elements, 3)
+	for _, child := range paragraph.Elements {
+		assert.True(t, child.Inline)
+		assert.Equal(t, paragraph, child.Parent)
+	}
+	emphasis := paragraph.Elements[1]
+	assert.Equal(t, "emphasis", emphasis.Type)
+	assert.Equal(t, emphasis, emphasis.Elements[0].Parent)
+}
+
+func TestParseInlinesSkipsCodeBlocks(t *testing.T) {
+	doc := Parse("```\n*not emphasis*\n```")
+
+	assert.Equal(t, "code", doc.Elements[0].Type)
+	assert.Empty(t, doc.Elements[0].Elements)
+}
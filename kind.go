@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NodeKind identifies kind of element
+type NodeKind int
+
+// Kinds of elements. Headings of every level share KindHeading, see HeadingLevel.
+const (
+	KindUnknown NodeKind = iota
+	KindDocument
+	KindHeading
+	KindParagraph
+	KindCode
+	KindTable
+	KindRow
+	KindCell
+	KindUnorderedList
+	KindOrderedList
+	KindListItem
+	KindBlockquote
+	KindThematicBreak
+	KindTOC
+	KindText
+	KindEmphasis
+	KindStrong
+	KindCodeSpan
+	KindLink
+	KindImage
+	KindFootnote
+	KindFootnoteReference
+	KindHTML
+)
+
+// kindNames provides name of each kind
+var kindNames = map[NodeKind]string{
+	KindUnknown:           "unknown",
+	KindDocument:          "document",
+	KindHeading:           "heading",
+	KindParagraph:         "paragraph",
+	KindCode:              "code",
+	KindTable:             "table",
+	KindRow:               "row",
+	KindCell:              "cell",
+	KindUnorderedList:     "unordered-list",
+	KindOrderedList:       "ordered-list",
+	KindListItem:          "list-item",
+	KindBlockquote:        "blockquote",
+	KindThematicBreak:     "thematic-break",
+	KindTOC:               "toc",
+	KindText:              "text",
+	KindEmphasis:          "emphasis",
+	KindStrong:            "strong",
+	KindCodeSpan:          "code-span",
+	KindLink:              "link",
+	KindImage:             "image",
+	KindFootnote:          "footnote",
+	KindFootnoteReference: "footnote-reference",
+	KindHTML:              "html",
+}
+
+// elementKinds maps element type to its kind. Text is paragraph or inline text depending on Inline.
+var elementKinds = map[string]NodeKind{
+	"doc":            KindDocument,
+	"h1":             KindHeading,
+	"h2":             KindHeading,
+	"h3":             KindHeading,
+	"h4":             KindHeading,
+	"h5":             KindHeading,
+	"h6":             KindHeading,
+	"text":           KindParagraph,
+	"code":           KindCode,
+	"table":          KindTable,
+	"row":            KindRow,
+	"cell":           KindCell,
+	"unordered-list": KindUnorderedList,
+	"ordered-list":   KindOrderedList,
+	"list-item":      KindListItem,
+	"blockquote":     KindBlockquote,
+	"hr":             KindThematicBreak,
+	"toc":            KindTOC,
+	"emphasis":       KindEmphasis,
+	"strong":         KindStrong,
+	"code-span":      KindCodeSpan,
+	"link":           KindLink,
+	"image":          KindImage,
+	"footnote":       KindFootnote,
+	"footnote-ref":   KindFootnoteReference,
+	"html":           KindHTML,
+}
+
+// String returns name of kind
+func (k NodeKind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "NodeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Kind returns kind of element derived from its type
+func (e *Element) Kind() NodeKind {
+	if e.Type == "text" && e.Inline {
+		return KindText
+	}
+	return elementKinds[e.Type]
+}
+
+// HeadingLevel returns level 1 to 6 of heading, or 0 for other elements
+func (e *Element) HeadingLevel() int {
+	if e.Kind() != KindHeading {
+		return 0
+	}
+	if e.Level > 0 {
+		return e.Level
+	}
+	return int(e.Type[1] - '0')
+}
+
+// HeadingID returns anchor id of heading
+func (e *Element) HeadingID() string {
+	if e.Kind() != KindHeading {
+		return ""
+	}
+	return e.Attributes["id"]
+}
+
+// CodeLanguage returns language of code block from its info string
+func (e *Element) CodeLanguage() string {
+	if e.Kind() != KindCode {
+		return ""
+	}
+	return e.Attributes["language"]
+}
+
+// CodeInfo returns info string of fenced code block
+func (e *Element) CodeInfo() string {
+	if e.Kind() != KindCode {
+		return ""
+	}
+	return e.Attributes["info"]
+}
+
+// CodeAttributes returns key=value attributes of code block info string
+func (e *Element) CodeAttributes() map[string]string {
+	if e.Kind() != KindCode {
+		return nil
+	}
+	attributes := map[string]string{}
+	for key, value := range e.Attributes {
+		if strings.HasPrefix(key, codeAttributePrefix) {
+			attributes[strings.TrimPrefix(key, codeAttributePrefix)] = value
+		}
+	}
+	return attributes
+}
+
+// ListOrdered checks whether element is ordered list or item of ordered list
+func (e *Element) ListOrdered() bool {
+	switch e.Kind() {
+	case KindOrderedList:
+		return true
+	case KindListItem:
+		return e.Parent != nil && e.Parent.Kind() == KindOrderedList
+	}
+	return false
+}
+
+// ListTight checks whether list items are not separated by blank lines
+func (e *Element) ListTight() bool {
+	switch e.Kind() {
+	case KindOrderedList, KindUnorderedList:
+		return e.Attributes["tight"] != "false"
+	}
+	return false
+}
+
+// ListStart returns number of first item of ordered list, or 0 for other elements
+func (e *Element) ListStart() int {
+	if e.Kind() != KindOrderedList {
+		return 0
+	}
+	start, err := strconv.Atoi(e.Attributes["start"])
+	if err != nil {
+		return 1
+	}
+	return start
+}
+
+// ListTask checks whether element is task list item
+func (e *Element) ListTask() bool {
+	if e.Kind() != KindListItem {
+		return false
+	}
+	_, ok := e.Attributes["checked"]
+	return ok
+}
+
+// TaskChecked checks whether element is checked task list item
+func (e *Element) TaskChecked() bool {
+	return e.ListTask() && e.Attributes["checked"] == "true"
+}
+
+// FootnoteLabel returns label of footnote definition or reference
+func (e *Element) FootnoteLabel() string {
+	switch e.Kind() {
+	case KindFootnote, KindFootnoteReference:
+		return e.Attributes["label"]
+	}
+	return ""
+}
+
+// FootnoteNumber returns number of referenced footnote definition or of its reference,
+// or 0 for other elements
+func (e *Element) FootnoteNumber() int {
+	switch e.Kind() {
+	case KindFootnote, KindFootnoteReference:
+		number, _ := strconv.Atoi(e.Attributes["number"])
+		return number
+	}
+	return 0
+}
+
+// CellAlign returns alignment of table cell: "left", "center", "right" or empty
+func (e *Element) CellAlign() string {
+	if e.Kind() != KindCell {
+		return ""
+	}
+	return e.Attributes["align"]
+}
+
+// Destination returns URL of link or source of image
+func (e *Element) Destination() string {
+	switch e.Kind() {
+	case KindLink:
+		return e.Attributes["href"]
+	case KindImage:
+		return e.Attributes["src"]
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElementKind(t *testing.T) {
+	doc := Parse("## Title\n\nSome *text*\n\n***")
+
+	h2 := doc.Elements[0]
+	paragraph := h2.Elements[1]
+	assert.Equal(t, KindDocument, doc.Kind())
+	assert.Equal(t, KindHeading, h2.Kind())
+	assert.Equal(t, KindText, h2.Elements[0].Kind())
+	assert.Equal(t, KindParagraph, paragraph.Kind())
+	assert.Equal(t, KindEmphasis, paragraph.Elements[1].Kind())
+	assert.Equal(t, KindThematicBreak, h2.Elements[2].Kind())
+	assert.Equal(t, KindHTML, Parse("<div>\n*a*\n</div>").Elements[0].Kind())
+	assert.Equal(t, KindUnknown, NewElement("custom", "").Kind())
+}
+
+func TestNodeKindString(t *testing.T) {
+	assert.Equal(t, "heading", KindHeading.String())
+	assert.Equal(t, "paragraph", KindParagraph.String())
+	assert.Equal(t, "list-item", KindListItem.String())
+	assert.Equal(t, "NodeKind(99)", NodeKind(99).String())
+}
+
+func TestHeadingLevel(t *testing.T) {
+	assert.Equal(t, 3, NewHeading(3, "Title").HeadingLevel())
+	assert.Equal(t, 2, NewElement("h2", "Title").HeadingLevel())
+	assert.Equal(t, 0, NewElement("text", "Title").HeadingLevel())
+}
+
+func TestHeadingID(t *testing.T) {
+	doc := Parse("# Hello World")
+
+	assert.Equal(t, "hello-world", doc.Elements[0].HeadingID())
+	assert.Equal(t, "", doc.Elements[0].Elements[0].HeadingID())
+}
+
+func TestCodeLanguageAndInfo(t *testing.T) {
+	code := NewCodeBlock("code", "go title=x")
+
+	assert.Equal(t, "go", code.CodeLanguage())
+	assert.Equal(t, "go title=x", code.CodeInfo())
+	assert.Equal(t, map[string]string{"title": "x"}, code.CodeAttributes())
+	assert.Equal(t, "", NewCodeBlock("code", "").CodeLanguage())
+	assert.Equal(t, "", NewElement("text", "go").CodeLanguage())
+}
+
+func TestListAccessors(t *testing.T) {
+	doc := Parse("3. one\n4. two\n\n- loose\n\n- list")
+
+	ordered := doc.Elements[0]
+	unordered := doc.Elements[1]
+	assert.True(t, ordered.ListOrdered())
+	assert.True(t, ordered.Elements[0].ListOrdered())
+	assert.False(t, unordered.ListOrdered())
+	assert.False(t, unordered.Elements[0].ListOrdered())
+	assert.True(t, ordered.ListTight())
+	assert.False(t, unordered.ListTight())
+	assert.Equal(t, 3, ordered.ListStart())
+	assert.Equal(t, 0, unordered.ListStart())
+}
+
+func TestCellAlign(t *testing.T) {
+	table := NewTable([][]string{[]string{"a", "b"}}, "center", "")
+
+	assert.Equal(t, "center", table.Elements[0].Elements[0].CellAlign())
+	assert.Equal(t, "", table.Elements[0].Elements[1].CellAlign())
+}
+
+func TestDestination(t *testing.T) {
+	doc := Parse("[link](/url) ![img](/img.png)")
+
+	paragraph := doc.Elements[0]
+	assert.Equal(t, "/url", paragraph.Elements[0].Destination())
+	assert.Equal(t, "/img.png", paragraph.Elements[2].Destination())
+	assert.Equal(t, "", paragraph.Destination())
+}
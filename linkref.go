@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// LinkReference is a link reference definition, [label]: destination "title"
+type LinkReference struct {
+	Label       string
+	Destination string
+	Title       string
+	Span        Span
+}
+
+// linkDestinationEscaper escapes destination written without angle brackets
+var linkDestinationEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"(", "\\(",
+	")", "\\)",
+	"<", "\\<",
+)
+
+// linkTitleEscaper escapes title written in double quotes
+var linkTitleEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\"", "\\\"",
+)
+
+// LinkReference returns link reference definition of label, matched case-insensitively
+func (d *Document) LinkReference(label string) (*LinkReference, bool) {
+	ref, ok := d.LinkReferences[normalizeLinkLabel(label)]
+	return ref, ok
+}
+
+// normalizeLinkLabel folds case and collapses whitespace of label
+// so that labels differing only in them match
+func normalizeLinkLabel(label string) string {
+	return strings.ToUpper(strings.ToLower(strings.Join(strings.Fields(label), " ")))
+}
+
+// linkLabelLength returns length of [label] at pos including brackets, or 0 when there is none.
+// Label may not contain unescaped brackets.
+func linkLabelLength(text string, pos int) int {
+	if pos >= len(text) || text[pos] != '[' {
+		return 0
+	}
+	for i := pos + 1; i < len(text) && i-pos <= 1000; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			return 0
+		case ']':
+			return i - pos + 1
+		}
+	}
+	return 0
+}
+
+// parseLinkReferenceDefinition parses [label]: destination "title" at the beginning of text.
+// It returns definition and length of text taken, including its line end.
+func parseLinkReferenceDefinition(text string) (*LinkReference, int, bool) {
+	pos := 0
+	for pos < 3 && pos < len(text) && text[pos] == ' ' {
+		pos++
+	}
+
+	n := linkLabelLength(text, pos)
+	if n == 0 || pos+n >= len(text) || text[pos+n] != ':' {
+		return nil, 0, false
+	}
+	label := text[pos+1 : pos+n-1]
+	if strings.TrimSpace(label) == "" || strings.HasPrefix(label, "^") {
+		// [^label]: is footnote definition
+		return nil, 0, false
+	}
+
+	start := skipLinkSpace(text, pos+n+1)
+	dest, pos, ok := parseLinkDestination(text, start)
+	if !ok || pos == start {
+		return nil, 0, false
+	}
+	ref := &LinkReference{
+		Label:       label,
+		Destination: unescapeMarkdown(dest),
+	}
+
+	// title must be separated from destination and followed by nothing else on its line
+	next := skipLinkSpace(text, pos)
+	if next > pos && next < len(text) && strings.IndexByte("\"'(", text[next]) >= 0 {
+		if title, end, ok := parseLinkTitle(text, next); ok {
+			if end, ok := skipToLineEnd(text, end); ok {
+				ref.Title = unescapeMarkdown(title)
+				return ref, end, true
+			}
+		}
+	}
+
+	end, ok := skipToLineEnd(text, pos)
+	if !ok {
+		return nil, 0, false
+	}
+	return ref, end, true
+}
+
+// skipToLineEnd returns position following line end when only spaces follow pos on its line
+func skipToLineEnd(text string, pos int) (int, bool) {
+	for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t') {
+		pos++
+	}
+	if pos == len(text) {
+		return pos, true
+	}
+	if text[pos] == '\n' {
+		return pos + 1, true
+	}
+	return 0, false
+}
+
+// extractLinkReferences collects link reference definitions at the beginning of block
+// starting at line. It returns the rest of block and number of lines taken by definitions.
+func (p *parser) extractLinkReferences(block string, line int) (string, int) {
+	taken := 0
+	for block != "" {
+		ref, n, ok := parseLinkReferenceDefinition(block)
+		if !ok {
+			break
+		}
+
+		ref.Span = p.lineSpan(line+taken, strings.TrimRight(block[:n], "\n"))
+		key := normalizeLinkLabel(ref.Label)
+		if _, ok := p.linkReferences[key]; ok {
+			p.warn(line+taken, "duplicate link reference definition ["+ref.Label+"]")
+		} else {
+			p.linkReferences[key] = ref
+		}
+
+		taken += strings.Count(block[:n], "\n")
+		block = block[n:]
+	}
+	return block, taken
+}
+
+// renderLinkReferences renders link reference definitions in order of appearance
+func renderLinkReferences(refs map[string]*LinkReference) string {
+	sorted := []*LinkReference{}
+	for _, ref := range refs {
+		sorted = append(sorted, ref)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Span.Start.Offset != sorted[j].Span.Start.Offset {
+			return sorted[i].Span.Start.Offset < sorted[j].Span.Start.Offset
+		}
+		return sorted[i].Label < sorted[j].Label
+	})
+
+	lines := []string{}
+	for _, ref := range sorted {
+		dest := linkDestinationEscaper.Replace(ref.Destination)
+		if ref.Destination == "" || strings.ContainsAny(ref.Destination, " \t\n") {
+			dest = "<" + strings.Replace(ref.Destination, "\\", "\\\\", -1) + ">"
+		}
+
+		line := "[" + ref.Label + "]: " + dest
+		if ref.Title != "" {
+			line += " \"" + linkTitleEscaper.Replace(ref.Title) + "\""
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
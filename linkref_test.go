@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinkReferenceDefinition(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected *LinkReference
+		length   int
+	}{
+		{"[foo]: /url \"title\"", &LinkReference{Label: "foo", Destination: "/url", Title: "title"}, 19},
+		{"   [Foo Bar]:\n  <my url>\n  'title'\nrest", &LinkReference{Label: "Foo Bar", Destination: "my url", Title: "title"}, 35},
+		{"[foo]: /url\n\"title\" ok", &LinkReference{Label: "foo", Destination: "/url"}, 12},
+		{"[foo]: /a\\(b (title)", &LinkReference{Label: "foo", Destination: "/a(b", Title: "title"}, 20},
+		{"[foo]: <>", &LinkReference{Label: "foo", Destination: ""}, 9},
+	}
+
+	for _, test := range tests {
+		ref, length, ok := parseLinkReferenceDefinition(test.text)
+
+		assert.True(t, ok, test.text)
+		assert.Equal(t, test.expected, ref, test.text)
+		assert.Equal(t, test.length, length, test.text)
+	}
+}
+
+func TestParseLinkReferenceDefinitionFailed(t *testing.T) {
+	for _, text := range []string{
+		"[foo]:",
+		"[foo]: /url trailing",
+		"[foo]: /url \"title\" trailing",
+		"[]: /url",
+		"[^1]: footnote",
+		"[a[b]]: /url",
+		"    [foo]: /url",
+		"[foo] /url",
+	} {
+		_, _, ok := parseLinkReferenceDefinition(text)
+
+		assert.False(t, ok, text)
+	}
+}
+
+func TestNormalizeLinkLabel(t *testing.T) {
+	assert.Equal(t, normalizeLinkLabel("Foo  Bar"), normalizeLinkLabel("foo\nbar"))
+	assert.NotEqual(t, normalizeLinkLabel("foo"), normalizeLinkLabel("foo bar"))
+}
+
+func TestParseLinkReferences(t *testing.T) {
+	doc := Parse("[Foo]: /url \"Title\"\n[bar]: /bar\nparagraph\n\n[foo]: /duplicate")
+
+	assert.Equal(t, 1, len(doc.Elements))
+	assert.Equal(t, "paragraph", doc.Elements[0].Text)
+	assert.Equal(t, 3, doc.Elements[0].Span.Start.Line)
+	assert.Equal(t, 2, len(doc.LinkReferences))
+
+	ref, ok := doc.LinkReference("FOO")
+	assert.True(t, ok)
+	assert.Equal(t, &LinkReference{
+		Label:       "Foo",
+		Destination: "/url",
+		Title:       "Title",
+		Span:        Span{Start: Position{Line: 1, Column: 1, Offset: 0}, End: Position{Line: 1, Column: 20, Offset: 19}},
+	}, ref)
+	assert.Equal(t, []Diagnostic{{Line: 5, Message: "duplicate link reference definition [foo]"}}, doc.Diagnostics)
+}
+
+func TestParseWithoutLinkReferences(t *testing.T) {
+	doc := Parse("text")
+
+	assert.Nil(t, doc.LinkReferences)
+	_, ok := doc.LinkReference("text")
+	assert.False(t, ok)
+}
+
+func TestParseReferenceLinks(t *testing.T) {
+	doc := Parse("[full][Ref] [ref][] [REF] ![img][ref] [missing][nope]\n\n[ref]: /url 'Title'")
+
+	paragraph := doc.Elements[0]
+	links := []*Element{}
+	for _, el := range paragraph.Elements {
+		if el.Kind() == KindLink || el.Kind() == KindImage {
+			links = append(links, el)
+		}
+	}
+
+	assert.Equal(t, 4, len(links))
+	assert.Equal(t, map[string]string{"href": "/url", "title": "Title"}, links[0].Attributes)
+	assert.Equal(t, "full", plainText(links[0].Elements))
+	assert.Equal(t, "ref", plainText(links[1].Elements))
+	assert.Equal(t, "REF", plainText(links[2].Elements))
+	assert.Equal(t, map[string]string{"src": "/url", "alt": "img", "title": "Title"}, links[3].Attributes)
+	assert.Equal(t, Span{Start: Position{Line: 1, Column: 1, Offset: 0}, End: Position{Line: 1, Column: 12, Offset: 11}}, links[0].Span)
+	assert.Equal(t, " [missing][nope]", paragraph.Elements[len(paragraph.Elements)-1].Text)
+}
+
+func TestRenderMarkdownLinkReferences(t *testing.T) {
+	doc := Parse("[b]: </my url> \"say \\\"hi\\\"\"\n\nSee [a] and [b].\n\n[a]: /a(b)")
+
+	result := renderMarkdown(t, doc)
+
+	assert.Equal(t, "See [a] and [b].\n\n[b]: </my url> \"say \\\"hi\\\"\"\n[a]: /a\\(b\\)\n", result)
+	assert.Equal(t, doc.LinkReferences["B"].Title, withoutSpans(Parse(result)).LinkReferences["B"].Title)
+}
@@ -0,0 +1,345 @@
+package parser
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MarkdownOption configures markdown rendering
+type MarkdownOption func(*markdownRenderer)
+
+// MarkdownNormalize renders headings as single line ATX headings, lists with - bullets
+// and . delimiters and code with backtick fences instead of original markers and fences.
+// Adjacent lists get alternate * bullets and ) delimiters so that they stay separate lists.
+func MarkdownNormalize() MarkdownOption {
+	return func(r *markdownRenderer) {
+		r.normalize = true
+	}
+}
+
+type markdownRenderer struct {
+	markers   map[*Element]string
+	normalize bool
+}
+
+// RenderMarkdown renders document back to canonical markdown: ATX headings, fenced code
+// with its original fence, aligned pipe tables and lists with their original markers
+func RenderMarkdown(doc *Document, w io.Writer, opts ...MarkdownOption) error {
+	r := &markdownRenderer{
+		markers: map[*Element]string{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	text := r.renderBlocks(doc.Elements, false)
+	if refs := renderLinkReferences(doc.LinkReferences); refs != "" {
+		text = strings.TrimLeft(text+"\n\n"+refs, "\n")
+	}
+	if doc.FrontMatter != nil {
+		text = strings.TrimRight(renderFrontMatter(doc.FrontMatter)+"\n\n"+text, "\n")
+	}
+	if text != "" {
+		text += "\n"
+	}
+
+	_, err := io.WriteString(w, text)
+	return err
+}
+
+// renderBlocks renders block elements separated by blank line, or by line break in tight list
+func (r *markdownRenderer) renderBlocks(elements []*Element, tight bool) string {
+	blocks := []string{}
+	var previous *Element
+	for _, el := range elements {
+		if !el.Inline {
+			blocks = append(blocks, r.renderBlock(el, previous, tight)...)
+			previous = el
+		}
+	}
+
+	if tight {
+		return strings.Join(blocks, "\n")
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderBlock renders element following previous sibling as markdown blocks.
+// Heading is followed by blocks of its section.
+func (r *markdownRenderer) renderBlock(el, previous *Element, tight bool) []string {
+	switch el.Kind() {
+	case KindHeading:
+		blocks := []string{r.renderHeading(el)}
+		previous = nil
+		for _, child := range el.Elements {
+			if !child.Inline {
+				blocks = append(blocks, r.renderBlock(child, previous, tight)...)
+				previous = child
+			}
+		}
+		return blocks
+	case KindCode:
+		return []string{r.renderCode(el)}
+	case KindTable:
+		return []string{renderTableMarkdown(el)}
+	case KindUnorderedList, KindOrderedList:
+		return []string{r.renderList(el, previous)}
+	case KindBlockquote:
+		return []string{prefixLines(r.renderBlocks(el.Elements, false), "> ", "> ")}
+	case KindThematicBreak:
+		if previous == nil && r.isItemMarkerLine(el, "*") {
+			// *** following * marker would read as a break in place of the list
+			return []string{"---"}
+		}
+		return []string{"***"}
+	case KindFootnote:
+		content := r.renderBlocks(el.Elements, false)
+		return []string{prefixLines(content, "[^"+el.Attributes["label"]+"]: ", "    ")}
+	}
+	return []string{el.Text}
+}
+
+// isItemMarkerLine checks whether element is written on the marker line of list item
+// whose list uses marker
+func (r *markdownRenderer) isItemMarkerLine(el *Element, marker string) bool {
+	item := el.Parent
+	return item != nil && item.Kind() == KindListItem && item.Text == "" && !item.ListTask() &&
+		r.markers[item.Parent] == marker
+}
+
+// renderHeading renders ATX heading, or setext heading when text spans several lines.
+// Normalized heading text is joined to a single line so that it is always ATX.
+// Only id written explicitly in source is written back, generated ids are left to the parser.
+func (r *markdownRenderer) renderHeading(el *Element) string {
+	text := el.Text
+	if r.normalize {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSpace(line)
+		}
+		text = strings.Join(lines, " ")
+	}
+	if id := el.Attributes["id"]; id != "" && el.Attributes["explicit-id"] == "true" {
+		text += " {#" + id + "}"
+	}
+
+	level := el.HeadingLevel()
+	if strings.Contains(text, "\n") && level <= 2 {
+		underline := "==="
+		if level == 2 {
+			underline = "---"
+		}
+		return text + "\n" + underline
+	}
+
+	if text == "" {
+		return strings.Repeat("#", level)
+	}
+	if atxClosingPattern.MatchString(text) {
+		// trailing # would be taken for closing sequence
+		text += " #"
+	}
+	return strings.Repeat("#", level) + " " + text
+}
+
+// renderCode renders code block with its original fence. Code without fence
+// is indented when possible.
+func (r *markdownRenderer) renderCode(el *Element) string {
+	fence := el.Attributes["fence"]
+	info := el.Attributes["info"]
+	if fence == "" && info == "" && canIndentCode(el.Text) {
+		return prefixLines(el.Text, "    ", "    ")
+	}
+	if fence == "" || r.normalize {
+		fence = codeFenceFor(el.Text)
+		if strings.Contains(info, "`") {
+			// backtick fence cannot have backtick in info string
+			fence = strings.Repeat("~", len(fence))
+		}
+	}
+
+	if el.Text == "" {
+		return fence + info + "\n" + fence
+	}
+	return fence + info + "\n" + el.Text + "\n" + fence
+}
+
+// canIndentCode checks whether code can be written as indented code block,
+// which cannot start or end with blank line
+func canIndentCode(text string) bool {
+	lines := strings.Split(text, "\n")
+	return strings.TrimSpace(lines[0]) != "" && strings.TrimSpace(lines[len(lines)-1]) != ""
+}
+
+// codeFenceFor returns backtick fence longer than any backtick fence inside text
+func codeFenceFor(text string) string {
+	length := 3
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimLeft(line, " ")
+		if n := countRun(line, 0, '`'); n >= length {
+			length = n + 1
+		}
+	}
+	return strings.Repeat("`", length)
+}
+
+// renderTableMarkdown renders table with columns padded to the same width
+func renderTableMarkdown(el *Element) string {
+	if len(el.Elements) == 0 {
+		return ""
+	}
+
+	header := el.Elements[0]
+	widths := make([]int, len(header.Elements))
+	aligns := make([]string, len(header.Elements))
+	for i, cell := range header.Elements {
+		aligns[i] = cell.Attributes["align"]
+		widths[i] = 3
+	}
+
+	rows := [][]string{}
+	for _, row := range el.Elements {
+		cells := make([]string, len(widths))
+		for i, cell := range row.Elements {
+			if i >= len(cells) {
+				break
+			}
+			cells[i] = strings.Replace(cell.Text, "|", "\\|", -1)
+			if n := utf8.RuneCountInString(cells[i]); n > widths[i] {
+				widths[i] = n
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		switch aligns[i] {
+		case "left":
+			separator[i] = ":" + strings.Repeat("-", width-1)
+		case "center":
+			separator[i] = ":" + strings.Repeat("-", width-2) + ":"
+		case "right":
+			separator[i] = strings.Repeat("-", width-1) + ":"
+		default:
+			separator[i] = strings.Repeat("-", width)
+		}
+	}
+
+	lines := []string{}
+	for i, cells := range rows {
+		for j := range cells {
+			cells[j] = padCell(cells[j], widths[j], aligns[j])
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "| "+strings.Join(separator, " | ")+" |")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// padCell pads cell text to width following column alignment
+func padCell(text string, width int, align string) string {
+	padding := width - utf8.RuneCountInString(text)
+	switch align {
+	case "right":
+		return strings.Repeat(" ", padding) + text
+	case "center":
+		return strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2)
+	}
+	return text + strings.Repeat(" ", padding)
+}
+
+// renderList renders list items with their original or normalized markers
+func (r *markdownRenderer) renderList(el, previous *Element) string {
+	tight := el.ListTight()
+	ordered := el.Kind() == KindOrderedList
+
+	marker := r.listMarker(el, previous)
+	delimiter := marker
+
+	items := []string{}
+	for i, item := range el.Elements {
+		if ordered {
+			number, ok := item.Attributes["number"]
+			if !ok {
+				number = strconv.Itoa(el.ListStart() + i)
+			}
+			marker = number + delimiter
+		}
+		items = append(items, r.renderListItem(item, marker, tight))
+	}
+
+	if tight {
+		return strings.Join(items, "\n")
+	}
+	return strings.Join(items, "\n\n")
+}
+
+// listMarker returns bullet of unordered list or delimiter of ordered list
+func (r *markdownRenderer) listMarker(el, previous *Element) string {
+	ordered := el.Kind() == KindOrderedList
+	marker := el.Attributes["marker"]
+	if ordered {
+		marker = el.Attributes["delimiter"]
+	}
+
+	if marker == "" || r.normalize {
+		preferred, alternate := "-", "*"
+		if ordered {
+			preferred, alternate = ".", ")"
+		}
+		marker = preferred
+		if previous != nil && previous.Kind() == el.Kind() && r.markers[previous] == preferred {
+			marker = alternate
+		}
+	}
+
+	r.markers[el] = marker
+	return marker
+}
+
+// renderListItem renders item content indented under its marker
+func (r *markdownRenderer) renderListItem(item *Element, marker string, tight bool) string {
+	blocks := []string{}
+	if item.ListTask() {
+		checkbox := "[ ] "
+		if item.TaskChecked() {
+			checkbox = "[x] "
+		}
+		blocks = append(blocks, checkbox+item.Text)
+	} else if item.Text != "" {
+		blocks = append(blocks, item.Text)
+	}
+	if content := r.renderBlocks(item.Elements, tight); content != "" {
+		blocks = append(blocks, content)
+	}
+
+	content := strings.Join(blocks, "\n\n")
+	if tight {
+		content = strings.Join(blocks, "\n")
+	}
+
+	return prefixLines(content, marker+" ", strings.Repeat(" ", len(marker)+1))
+}
+
+// prefixLines adds first to the first line of text and rest to the other lines.
+// Trailing spaces of prefix are left out on blank lines.
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
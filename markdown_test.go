@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// renderMarkdown renders document to string for comparison
+func renderMarkdown(t *testing.T, doc *Document) string {
+	var out bytes.Buffer
+	assert.NoError(t, RenderMarkdown(doc, &out))
+	return out.String()
+}
+
+func TestRenderMarkdownRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "html", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+
+		t.Run(name, func(t *testing.T) {
+			content, err := ioutil.ReadFile(file)
+			assert.NoError(t, err)
+
+			doc := withoutSpans(Parse(string(content)))
+			rendered := renderMarkdown(t, doc)
+			result := withoutSpans(Parse(rendered))
+
+			assert.Equal(t, doc.Element, result.Element, rendered)
+			assert.Equal(t, rendered, renderMarkdown(t, result))
+		})
+	}
+}
+
+func TestRenderMarkdownHeadings(t *testing.T) {
+	content := "Title\n===\n\n## Section ##\n\nMulti\nline\n---\n\n# Custom {#my-id}\n\n# Title\n\n#"
+
+	result := renderMarkdown(t, Parse(content))
+
+	assert.Equal(t, "# Title\n\n## Section\n\nMulti\nline\n---\n\n# Custom {#my-id}\n\n# Title\n\n#\n", result)
+}
+
+func TestRenderMarkdownRoundTripsAmbiguousLines(t *testing.T) {
+	contents := map[string]string{
+		"Foo #\n===":                            "# Foo # #\n",
+		"## a # #":                              "## a # #\n",
+		"### Foo \\#":                           "### Foo \\#\n",
+		"* ---\n* a":                            "* ---\n* a\n",
+		"- ***":                                 "- ***\n",
+		"* a\n\n  ***":                          "* a\n\n  ***\n",
+		"* item\n    * nested\n\n    more text": "* item\n\n  * nested\n\n  more text\n",
+		"* \n   0":                              "* 0\n",
+	}
+
+	for content, expected := range contents {
+		doc := withoutSpans(Parse(content))
+		rendered := renderMarkdown(t, doc)
+
+		assert.Equal(t, expected, rendered, content)
+		assert.Equal(t, doc.Element, withoutSpans(Parse(rendered)).Element, content)
+	}
+}
+
+func TestRenderMarkdownCode(t *testing.T) {
+	content := "~~~~python title=\"x\"\nprint()\n~~~~\n\n    indented\n\n```\n```"
+
+	result := renderMarkdown(t, Parse(content))
+
+	assert.Equal(t, "~~~~python title=\"x\"\nprint()\n~~~~\n\n    indented\n\n```\n```\n", result)
+}
+
+func TestRenderMarkdownCodeWithoutFence(t *testing.T) {
+	doc := NewDocument()
+	doc.Append(NewCodeBlock("```\ncode", "go"))
+	doc.Append(NewCodeBlock("\nblank first", ""))
+
+	result := renderMarkdown(t, doc)
+
+	assert.Equal(t, "````go\n```\ncode\n````\n\n```\n\nblank first\n```\n", result)
+}
+
+func TestRenderMarkdownAlignedTable(t *testing.T) {
+	content := "a|Center|right\n:-|:-:|-:\nlong cell|x|pipe \\| here"
+
+	result := renderMarkdown(t, Parse(content))
+
+	assert.Equal(t, "| a         | Center |        right |\n"+
+		"| :-------- | :----: | -----------: |\n"+
+		"| long cell |   x    | pipe \\| here |\n", result)
+}
+
+func TestRenderMarkdownLists(t *testing.T) {
+	content := "+ one\n+ two\n  * nested\n\n3) three\n\n4) four\n\n   para"
+
+	result := renderMarkdown(t, Parse(content))
+
+	assert.Equal(t, "+ one\n+ two\n  * nested\n\n3) three\n\n4) four\n\n   para\n", result)
+}
+
+func TestRenderMarkdownBlockquoteAndBreak(t *testing.T) {
+	content := "> quote\nlazy\n>\n> - item\n\n---\n\n[[TOC]]"
+
+	result := renderMarkdown(t, Parse(content))
+
+	assert.Equal(t, "> quote\n> lazy\n>\n> - item\n\n***\n\n[[TOC]]\n", result)
+}
+
+func TestRenderMarkdownEditedTree(t *testing.T) {
+	doc := Parse("# Title\n\n| a | b |\n|---|---|\n| 1 | 2 |")
+	table := doc.Elements[0].Elements[1]
+	row := NewElement("row", "")
+	row.Append(NewElement("cell", "3"))
+	row.Append(NewElement("cell", "4"))
+	table.Append(row)
+	doc.Elements[0].Text = "New title"
+
+	result := renderMarkdown(t, doc)
+
+	assert.Equal(t, "# New title\n\n| a   | b   |\n| --- | --- |\n| 1   | 2   |\n| 3   | 4   |\n", result)
+}
+
+func TestRenderMarkdownEmptyDocument(t *testing.T) {
+	assert.Equal(t, "", renderMarkdown(t, Parse("")))
+}
+
+func TestRenderMarkdownNormalize(t *testing.T) {
+	content := "Multi\nline\n===\n\n* a\n* b\n\n+ c\n\n1) x\n\n~~~go\nfmt()\n~~~\n\n~~~ a`b\n```\n~~~"
+
+	var out bytes.Buffer
+	assert.NoError(t, RenderMarkdown(Parse(content), &out, MarkdownNormalize()))
+
+	assert.Equal(t, "# Multi line\n\n- a\n- b\n\n* c\n\n1. x\n\n```go\nfmt()\n```\n\n~~~~a`b\n```\n~~~~\n", out.String())
+	assert.Equal(t, withoutSpans(Parse(content)).Elements[1:], withoutSpans(Parse(out.String())).Elements[1:])
+}
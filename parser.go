@@ -1,17 +1,76 @@
 package parser
 
+import "strings"
+
+// parser holds state of a single parsing run
+type parser struct {
+	diagnostics    []Diagnostic
+	lines          []string
+	offsets        []int
+	footnotes      map[string]*Element
+	linkReferences map[string]*LinkReference
+}
+
+func newParser() *parser {
+	return &parser{
+		footnotes:      map[string]*Element{},
+		linkReferences: map[string]*LinkReference{},
+	}
+}
+
 // Parse markdown text to document
 func Parse(content string) *Document {
 	doc := NewDocument()
-	var cursor = doc.Element
+	p := newParser()
+	p.loadSource(content)
+	content = strings.Replace(content, "\r\n", "\n", -1)
+
+	frontMatter, body, lineCount := p.splitFrontMatter(content)
+	doc.FrontMatter = frontMatter
+
+	p.parseBlocks(body, lineCount+1, doc.Element)
+	if len(p.linkReferences) > 0 {
+		doc.LinkReferences = p.linkReferences
+	}
+	p.parseInlines(doc.Element)
+	p.assignHeadingIDs(doc.Element)
+	numberFootnotes(doc.Element)
+
+	if len(p.diagnostics) > 0 {
+		sortDiagnostics(p.diagnostics)
+		doc.Diagnostics = p.diagnostics
+	}
+
+	return doc
+}
+
+// parseBlocks parses markdown blocks in content starting at line and appends them to container
+func (p *parser) parseBlocks(content string, line int, container *Element) {
+	var cursor = container
 	tokenizer := NewTokenizer()
 
-	blocks := tokenizer.Tokenize(content)
+	tokenizer.Tokenize(content)
+	lines := strings.Split(content, "\n")
+
+	for _, diagnostic := range tokenizer.Diagnostics {
+		p.warn(line+diagnostic.Line-1, diagnostic.Message)
+	}
 
-	for _, block := range blocks {
-		if block != "" {
-			element := createElement(block)
-			for ElementHierarchy[cursor.Type] >= ElementHierarchy[element.Type] {
+	for _, token := range tokenizer.Tokens {
+		// link reference definitions are collected and left out of the tree
+		text, taken := p.extractLinkReferences(token.Text, line+token.Line-1)
+		if taken > 0 {
+			token.Line += taken
+			token.Span.Start = Position{Line: token.Span.Start.Line + taken, Column: 1}
+		}
+
+		if text != "" {
+			element := p.createElement(text, line+token.Line-1)
+			element.Span = Span{
+				Start: p.sourcePosition(token.Span.Start, lines, line),
+				End:   p.sourcePosition(token.Span.End, lines, line),
+			}
+			for cursor != container && ElementHierarchy[cursor.Type] >= ElementHierarchy[element.Type] {
 				cursor = cursor.Parent
 			}
 
@@ -20,6 +79,12 @@ func Parse(content string) *Document {
 			cursor = element
 		}
 	}
+}
 
-	return doc
+// warn records diagnostic at line
+func (p *parser) warn(line int, message string) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{
+		Line:    line,
+		Message: message,
+	})
 }
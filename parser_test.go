@@ -6,6 +6,34 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// withInlineText prepends expected inline text node holding element text
+func withInlineText(elements ...*Element) {
+	for _, el := range elements {
+		el.Elements = append([]*Element{
+			&Element{
+				Text:     el.Text,
+				Type:     "text",
+				Inline:   true,
+				Parent:   el,
+				Elements: []*Element{},
+			},
+		}, el.Elements...)
+	}
+}
+
+// withoutSpans clears source spans of document elements to compare structure only
+func withoutSpans(doc *Document) *Document {
+	clearSpans(doc.Element)
+	return doc
+}
+
+func clearSpans(el *Element) {
+	el.Span = Span{}
+	for _, child := range el.Elements {
+		clearSpans(child)
+	}
+}
+
 func TestParseEmptyContent(t *testing.T) {
 	content := ""
 	expected := &Document{
@@ -15,7 +43,7 @@ func TestParseEmptyContent(t *testing.T) {
 		},
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -29,7 +57,7 @@ func TestParseEmptyLineContent(t *testing.T) {
 		},
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -37,12 +65,21 @@ func TestParseEmptyLineContent(t *testing.T) {
 func TestParseSimpleDocument(t *testing.T) {
 	content := "Test"
 
-	Text := &Element{
+	TextInline := &Element{
 		Text:     "Test",
 		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	Text := &Element{
+		Text: "Test",
+		Type: "text",
+		Elements: []*Element{
+			TextInline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -50,13 +87,14 @@ func TestParseSimpleDocument(t *testing.T) {
 		},
 	}
 
+	TextInline.Parent = Text
 	Text.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -64,18 +102,36 @@ func TestParseSimpleDocument(t *testing.T) {
 func TestParseSimpleDocumentWith2SimpleParagraph(t *testing.T) {
 	content := "Test\n\nTest2"
 
-	Text1 := &Element{
+	Text1Inline := &Element{
 		Text:     "Test",
 		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
-	Text2 := &Element{
+	Text1 := &Element{
+		Text: "Test",
+		Type: "text",
+		Elements: []*Element{
+			Text1Inline,
+		},
+	}
+
+	Text2Inline := &Element{
 		Text:     "Test2",
 		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	Text2 := &Element{
+		Text: "Test2",
+		Type: "text",
+		Elements: []*Element{
+			Text2Inline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -84,6 +140,8 @@ func TestParseSimpleDocumentWith2SimpleParagraph(t *testing.T) {
 		},
 	}
 
+	Text1Inline.Parent = Text1
+	Text2Inline.Parent = Text2
 	Text1.Parent = Doc
 	Text2.Parent = Doc
 
@@ -91,7 +149,7 @@ func TestParseSimpleDocumentWith2SimpleParagraph(t *testing.T) {
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -99,12 +157,21 @@ func TestParseSimpleDocumentWith2SimpleParagraph(t *testing.T) {
 func TestParseSimpleDocumentWith1SimpleParagraphWithNewLine(t *testing.T) {
 	content := "Test\nTest2"
 
-	Text1 := &Element{
+	Text1Inline := &Element{
 		Text:     "Test\nTest2",
 		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	Text1 := &Element{
+		Text: "Test\nTest2",
+		Type: "text",
+		Elements: []*Element{
+			Text1Inline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -112,13 +179,14 @@ func TestParseSimpleDocumentWith1SimpleParagraphWithNewLine(t *testing.T) {
 		},
 	}
 
+	Text1Inline.Parent = Text1
 	Text1.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -126,12 +194,25 @@ func TestParseSimpleDocumentWith1SimpleParagraphWithNewLine(t *testing.T) {
 func TestParseH1Document(t *testing.T) {
 	content := "# Title"
 
-	H1 := &Element{
+	H1Inline := &Element{
 		Text:     "Title",
-		Type:     "h1",
+		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	H1 := &Element{
+		Text:  "Title",
+		Type:  "h1",
+		Level: 1,
+		Attributes: map[string]string{
+			"id": "title",
+		},
+		Elements: []*Element{
+			H1Inline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -139,13 +220,14 @@ func TestParseH1Document(t *testing.T) {
 		},
 	}
 
+	H1Inline.Parent = H1
 	H1.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -153,12 +235,25 @@ func TestParseH1Document(t *testing.T) {
 func TestParseAlternateH1Document(t *testing.T) {
 	content := "Title\n=="
 
-	H1 := &Element{
+	H1Inline := &Element{
 		Text:     "Title",
-		Type:     "h1",
+		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	H1 := &Element{
+		Text:  "Title",
+		Type:  "h1",
+		Level: 1,
+		Attributes: map[string]string{
+			"id": "title",
+		},
+		Elements: []*Element{
+			H1Inline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -166,13 +261,14 @@ func TestParseAlternateH1Document(t *testing.T) {
 		},
 	}
 
+	H1Inline.Parent = H1
 	H1.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -180,12 +276,25 @@ func TestParseAlternateH1Document(t *testing.T) {
 func TestParseH2Document(t *testing.T) {
 	content := "## Title"
 
-	H2 := &Element{
+	H2Inline := &Element{
 		Text:     "Title",
-		Type:     "h2",
+		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	H2 := &Element{
+		Text:  "Title",
+		Type:  "h2",
+		Level: 2,
+		Attributes: map[string]string{
+			"id": "title",
+		},
+		Elements: []*Element{
+			H2Inline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -193,13 +302,14 @@ func TestParseH2Document(t *testing.T) {
 		},
 	}
 
+	H2Inline.Parent = H2
 	H2.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -207,12 +317,25 @@ func TestParseH2Document(t *testing.T) {
 func TestParseAlternateH2Document(t *testing.T) {
 	content := "Title\n--"
 
-	H2 := &Element{
+	H2Inline := &Element{
 		Text:     "Title",
-		Type:     "h2",
+		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
+	H2 := &Element{
+		Text:  "Title",
+		Type:  "h2",
+		Level: 2,
+		Attributes: map[string]string{
+			"id": "title",
+		},
+		Elements: []*Element{
+			H2Inline,
+		},
+	}
+
 	Doc := &Element{
 		Type: "doc",
 		Elements: []*Element{
@@ -220,13 +343,14 @@ func TestParseAlternateH2Document(t *testing.T) {
 		},
 	}
 
+	H2Inline.Parent = H2
 	H2.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -234,16 +358,41 @@ func TestParseAlternateH2Document(t *testing.T) {
 func TestParseH1H2DocumentWithHierarchy(t *testing.T) {
 	content := "# H1 Title\n\n## H2 Title"
 
-	H2 := &Element{
+	H2Inline := &Element{
 		Text:     "H2 Title",
-		Type:     "h2",
+		Type:     "text",
+		Inline:   true,
+		Elements: []*Element{},
+	}
+
+	H2 := &Element{
+		Text:  "H2 Title",
+		Type:  "h2",
+		Level: 2,
+		Attributes: map[string]string{
+			"id": "h2-title",
+		},
+		Elements: []*Element{
+			H2Inline,
+		},
+	}
+
+	H1Inline := &Element{
+		Text:     "H1 Title",
+		Type:     "text",
+		Inline:   true,
 		Elements: []*Element{},
 	}
 
 	H1 := &Element{
-		Text: "H1 Title",
-		Type: "h1",
+		Text:  "H1 Title",
+		Type:  "h1",
+		Level: 1,
+		Attributes: map[string]string{
+			"id": "h1-title",
+		},
 		Elements: []*Element{
+			H1Inline,
 			H2,
 		},
 	}
@@ -255,14 +404,16 @@ func TestParseH1H2DocumentWithHierarchy(t *testing.T) {
 		},
 	}
 
+	H2Inline.Parent = H2
 	H2.Parent = H1
+	H1Inline.Parent = H1
 	H1.Parent = Doc
 
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -452,8 +603,12 @@ func TestParseTableDocument(t *testing.T) {
 	}
 
 	H1 := &Element{
-		Text: "Table Document",
-		Type: "h1",
+		Text:  "Table Document",
+		Type:  "h1",
+		Level: 1,
+		Attributes: map[string]string{
+			"id": "table-document",
+		},
 		Elements: []*Element{
 			Table,
 		},
@@ -489,11 +644,19 @@ func TestParseTableDocument(t *testing.T) {
 	Row4Col3.Parent = Row4
 	Row4Col4.Parent = Row4
 
+	withInlineText(
+		H1,
+		Row1Col1, Row1Col2, Row1Col3, Row1Col4,
+		Row2Col1, Row2Col2, Row2Col3, Row2Col4,
+		Row3Col1, Row3Col2, Row3Col3, Row3Col4,
+		Row4Col1, Row4Col2, Row4Col3, Row4Col4,
+	)
+
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -525,6 +688,10 @@ func TestParseUnorderedList(t *testing.T) {
 	List := &Element{
 		Type: "unordered-list",
 		Text: "",
+		Attributes: map[string]string{
+			"marker": "*",
+			"tight":  "true",
+		},
 		Elements: []*Element{
 			ListItem1,
 			ListItem2,
@@ -545,11 +712,13 @@ func TestParseUnorderedList(t *testing.T) {
 	ListItem2.Parent = List
 	ListItem3.Parent = List
 
+	withInlineText(ListItem1, ListItem2, ListItem3)
+
 	expected := &Document{
 		Element: Doc,
 	}
 
-	result := Parse(content)
+	result := withoutSpans(Parse(content))
 
 	assert.Equal(t, expected, result)
 }
@@ -558,21 +727,30 @@ func TestParseOrderedList(t *testing.T) {
 	content := "12. item 1\n1. item 2\n728123123121234511. item 3"
 
 	ListItem1 := &Element{
-		Type:     "list-item",
+		Type: "list-item",
+		Attributes: map[string]string{
+			"number": "12",
+		},
 		Elements: []*Element{},
 		Text:     "item 1",
 		Parent:   nil,
 	}
 
 	ListItem2 := &Element{
-		Type:     "list-item",
+		Type: "list-item",
+		Attributes: map[string]string{
+			"number": "1",
+		},
 		Elements: []*Element{},
 		Text:     "item 2",
 		Parent:   nil,
 	}
 
 	ListItem3 := &Element{
-		Type:     "list-item",
+		Type: "list-item",
+		Attributes: map[string]string{
+			"number": "728123123121234511",
+		},
 		Elements: []*Element{},
 		Text:     "item 3",
 		Parent:   nil,
@@ -581,6 +759,11 @@ func TestParseOrderedList(t *testing.T) {
 	List := &Element{
 		Type: "ordered-list",
 		Text: "",
+		Attributes: map[string]string{
+			"delimiter": ".",
+			"start":     "12",
+			"tight":     "true",
+		},
 		Elements: []*Element{
 			ListItem1,
 			ListItem2,
@@ -601,11 +784,438 @@ func TestParseOrderedList(t *testing.T) {
 	ListItem2.Parent = List
 	ListItem3.Parent = List
 
+	withInlineText(ListItem1, ListItem2, ListItem3)
+
 	expected := &Document{
 		Element: Doc,
 	}
 
+	result := withoutSpans(Parse(content))
+
+	assert.Equal(t, expected, result)
+}
+
+func TestParseBlockquote(t *testing.T) {
+	content := "> # Quote\n>\n> Quoted\n>\n> > Nested\n\nAfter"
+
+	Nested := &Element{
+		Type:     "text",
+		Text:     "Nested",
+		Elements: []*Element{},
+	}
+
+	InnerQuote := &Element{
+		Type: "blockquote",
+		Elements: []*Element{
+			Nested,
+		},
+	}
+
+	Quoted := &Element{
+		Type:     "text",
+		Text:     "Quoted",
+		Elements: []*Element{},
+	}
+
+	H1 := &Element{
+		Type:  "h1",
+		Level: 1,
+		Text:  "Quote",
+		Attributes: map[string]string{
+			"id": "quote",
+		},
+		Elements: []*Element{
+			Quoted,
+			InnerQuote,
+		},
+	}
+
+	Quote := &Element{
+		Type: "blockquote",
+		Elements: []*Element{
+			H1,
+		},
+	}
+
+	After := &Element{
+		Type:     "text",
+		Text:     "After",
+		Elements: []*Element{},
+	}
+
+	Doc := &Element{
+		Type: "doc",
+		Elements: []*Element{
+			Quote,
+			After,
+		},
+	}
+
+	Nested.Parent = InnerQuote
+	InnerQuote.Parent = H1
+	Quoted.Parent = H1
+	H1.Parent = Quote
+	Quote.Parent = Doc
+	After.Parent = Doc
+
+	withInlineText(Nested, Quoted, H1, After)
+
+	expected := &Document{
+		Element: Doc,
+	}
+
+	result := withoutSpans(Parse(content))
+
+	assert.Equal(t, expected, result)
+}
+
+func TestParseBlockquoteUnderHeading(t *testing.T) {
+	content := "# Title\n\n> Quoted\n\nAfter"
+
 	result := Parse(content)
 
+	assert.Len(t, result.Elements, 1)
+	H1 := result.Elements[0]
+	assert.Len(t, H1.Elements, 3)
+	assert.True(t, H1.Elements[0].Inline)
+	assert.Equal(t, "blockquote", H1.Elements[1].Type)
+	assert.Equal(t, "text", H1.Elements[2].Type)
+	assert.Equal(t, "After", H1.Elements[2].Text)
+}
+
+func TestParseNestedBlockquoteWithoutBlankLine(t *testing.T) {
+	for _, content := range []string{"> a\n>> b", "> a\n> > b"} {
+		result := Parse(content)
+
+		assert.Len(t, result.Elements, 1)
+		Quote := result.Elements[0]
+		assert.Equal(t, "blockquote", Quote.Type)
+		assert.Len(t, Quote.Elements, 2)
+		assert.Equal(t, "text", Quote.Elements[0].Type)
+		assert.Equal(t, "a", Quote.Elements[0].Text)
+		assert.Equal(t, "blockquote", Quote.Elements[1].Type)
+		assert.Len(t, Quote.Elements[1].Elements, 1)
+		assert.Equal(t, "b", Quote.Elements[1].Elements[0].Text)
+	}
+}
+
+func TestParseBlockquoteInterruptsParagraph(t *testing.T) {
+	result := Parse("para\n> q")
+
+	assert.Len(t, result.Elements, 2)
+	assert.Equal(t, "text", result.Elements[0].Type)
+	assert.Equal(t, "para", result.Elements[0].Text)
+	assert.Equal(t, "blockquote", result.Elements[1].Type)
+	assert.Equal(t, "q", result.Elements[1].Elements[0].Text)
+}
+
+func TestParseBlockquoteInterruptsList(t *testing.T) {
+	result := Parse("- a\n> q")
+
+	assert.Len(t, result.Elements, 2)
+	assert.Equal(t, "unordered-list", result.Elements[0].Type)
+	assert.Len(t, result.Elements[0].Elements, 1)
+	assert.Equal(t, "blockquote", result.Elements[1].Type)
+	assert.Equal(t, "q", result.Elements[1].Elements[0].Text)
+}
+
+func TestParseNestedList(t *testing.T) {
+	content := "* item 1\n  * nested\n* item 2"
+
+	NestedItem := &Element{
+		Type:     "list-item",
+		Text:     "nested",
+		Elements: []*Element{},
+	}
+
+	NestedList := &Element{
+		Type: "unordered-list",
+		Attributes: map[string]string{
+			"marker": "*",
+			"tight":  "true",
+		},
+		Elements: []*Element{
+			NestedItem,
+		},
+	}
+
+	ListItem1 := &Element{
+		Type: "list-item",
+		Text: "item 1",
+		Elements: []*Element{
+			NestedList,
+		},
+	}
+
+	ListItem2 := &Element{
+		Type:     "list-item",
+		Text:     "item 2",
+		Elements: []*Element{},
+	}
+
+	List := &Element{
+		Type: "unordered-list",
+		Attributes: map[string]string{
+			"marker": "*",
+			"tight":  "true",
+		},
+		Elements: []*Element{
+			ListItem1,
+			ListItem2,
+		},
+	}
+
+	Doc := &Element{
+		Type: "doc",
+		Elements: []*Element{
+			List,
+		},
+	}
+
+	NestedItem.Parent = NestedList
+	NestedList.Parent = ListItem1
+	ListItem1.Parent = List
+	ListItem2.Parent = List
+	List.Parent = Doc
+
+	withInlineText(NestedItem, ListItem1, ListItem2)
+
+	expected := &Document{
+		Element: Doc,
+	}
+
+	result := withoutSpans(Parse(content))
+
 	assert.Equal(t, expected, result)
 }
+
+func TestParseLooseList(t *testing.T) {
+	content := "1. item 1\n\n2. item 2\n\nparagraph"
+
+	result := Parse(content)
+
+	assert.Len(t, result.Elements, 2)
+	assert.Equal(t, "ordered-list", result.Elements[0].Type)
+	assert.Equal(t, "false", result.Elements[0].Attributes["tight"])
+	assert.Len(t, result.Elements[0].Elements, 2)
+	assert.Equal(t, "text", result.Elements[1].Type)
+}
+
+func TestParseFencedCodeSpecExamples(t *testing.T) {
+	type block struct {
+		Type string
+		Text string
+	}
+	cases := []struct {
+		content  string
+		expected []block
+	}{
+		{"``\nfoo\n``", []block{{"text", "``\nfoo\n``"}}},
+		{"```\naaa\n~~~\n```", []block{{"code", "aaa\n~~~"}}},
+		{"~~~\naaa\n```\n~~~", []block{{"code", "aaa\n```"}}},
+		{"````\naaa\n```\n``````", []block{{"code", "aaa\n```"}}},
+		{"~~~~\naaa\n~~~\n~~~~", []block{{"code", "aaa\n~~~"}}},
+		{"```", []block{{"code", ""}}},
+		{"`````\n\n```\naaa", []block{{"code", "\n```\naaa"}}},
+		{"```\n\n  \n```", []block{{"code", "\n  "}}},
+		{"```\n```", []block{{"code", ""}}},
+		{" ```\n aaa\naaa\n```", []block{{"code", "aaa\naaa"}}},
+		{"  ```\naaa\n  aaa\naaa\n  ```", []block{{"code", "aaa\naaa\naaa"}}},
+		{"   ```\n   aaa\n    aaa\n  aaa\n   ```", []block{{"code", "aaa\n aaa\naaa"}}},
+		{"```\naaa\n  ```", []block{{"code", "aaa"}}},
+		{"   ```\naaa\n  ```", []block{{"code", "aaa"}}},
+		{"```\naaa\n    ```", []block{{"code", "aaa\n    ```"}}},
+		{"``` ```\naaa", []block{{"text", "``` ```\naaa"}}},
+		{"~~~~~~\naaa\n~~~ ~~", []block{{"code", "aaa\n~~~ ~~"}}},
+		{"foo\n```\nbar\n```\nbaz", []block{{"text", "foo"}, {"code", "bar"}, {"text", "baz"}}},
+		{"```\n``` aaa\n```", []block{{"code", "``` aaa"}}},
+	}
+
+	for _, c := range cases {
+		doc := Parse(c.content)
+
+		result := []block{}
+		for _, el := range doc.Elements {
+			result = append(result, block{el.Type, el.Text})
+		}
+		assert.Equal(t, c.expected, result, c.content)
+	}
+}
+
+func TestParseFencedCodeInListItem(t *testing.T) {
+	content := "1. item\n   ```\n   code\n\n   more\n   ```\n2. next"
+
+	doc := Parse(content)
+
+	assert.Len(t, doc.Elements, 1)
+	list := doc.Elements[0]
+	assert.Len(t, list.Elements, 2)
+	code := list.Elements[0].Elements[len(list.Elements[0].Elements)-1]
+	assert.Equal(t, "code", code.Type)
+	assert.Equal(t, "code\n\nmore", code.Text)
+}
+
+func TestParseCRLFLineEndings(t *testing.T) {
+	content := "# T\r\n\r\n```go\r\ncode\r\n```\r\n\r\nafter"
+
+	doc := Parse(content)
+
+	assert.Empty(t, doc.Diagnostics)
+	h1 := doc.Elements[0]
+	assert.Equal(t, "T", h1.Text)
+	assert.Len(t, h1.Elements, 3)
+	code := h1.Elements[1]
+	assert.Equal(t, "code", code.Type)
+	assert.Equal(t, "code", code.Text)
+	assert.Equal(t, "```go\r\ncode\r\n```", sourceOf(content, code))
+	assert.Equal(t, "after", h1.Elements[2].Text)
+	assert.Equal(t, "after", sourceOf(content, h1.Elements[2]))
+}
+
+func TestParseIndentedCodeSpecExamples(t *testing.T) {
+	type block struct {
+		Type string
+		Text string
+	}
+	cases := []struct {
+		content  string
+		expected []block
+	}{
+		{"    a simple\n      indented code block", []block{{"code", "a simple\n  indented code block"}}},
+		{"    <a/>\n    *hi*\n\n    - one", []block{{"code", "<a/>\n*hi*\n\n- one"}}},
+		{"    chunk1\n\n    chunk2\n  \n \n \n    chunk3", []block{{"code", "chunk1\n\nchunk2\n\n\n\nchunk3"}}},
+		{"Foo\n    bar", []block{{"text", "Foo\nbar"}}},
+		{"    foo\nbar", []block{{"code", "foo"}, {"text", "bar"}}},
+		{"        foo\n    bar", []block{{"code", "    foo\nbar"}}},
+		{"\n    \n    foo\n    ", []block{{"code", "foo"}}},
+		{"    foo  ", []block{{"code", "foo  "}}},
+	}
+
+	for _, c := range cases {
+		doc := Parse(c.content)
+
+		result := []block{}
+		for _, el := range doc.Elements {
+			result = append(result, block{el.Type, el.Text})
+		}
+		assert.Equal(t, c.expected, result, c.content)
+	}
+}
+
+func TestParseIndentedCodeInListItem(t *testing.T) {
+	content := "1.  item\n\n        code\n2.  next"
+
+	doc := Parse(content)
+
+	item := doc.Elements[0].Elements[0]
+	assert.Equal(t, "item", item.Text)
+	code := item.Elements[len(item.Elements)-1]
+	assert.Equal(t, "code", code.Type)
+	assert.Equal(t, "code", code.Text)
+}
+
+func TestParseListItemsWithTabAfterMarker(t *testing.T) {
+	unordered := Parse("-\tfoo\n\n    bar").Elements[0]
+	ordered := Parse("1.\tone").Elements[0]
+	code := Parse("-\t\tfoo").Elements[0].Elements[0].Elements[0]
+
+	assert.Equal(t, "unordered-list", unordered.Type)
+	assert.Equal(t, "foo", unordered.Elements[0].Text)
+	assert.Equal(t, "bar", unordered.Elements[0].Elements[len(unordered.Elements[0].Elements)-1].Text)
+	assert.Equal(t, "ordered-list", ordered.Type)
+	assert.Equal(t, "one", ordered.Elements[0].Text)
+	assert.Equal(t, "code", code.Type)
+	assert.Equal(t, "  foo", code.Text)
+}
+
+func TestParseTabIndentedListItemContent(t *testing.T) {
+	doc := Parse("- foo\n\n\tbar")
+
+	item := doc.Elements[0].Elements[0]
+	last := item.Elements[len(item.Elements)-1]
+	assert.Equal(t, "text", last.Type)
+	assert.Equal(t, "bar", last.Text)
+
+	doc = Parse("- foo\n\n\t\tbar")
+
+	item = doc.Elements[0].Elements[0]
+	last = item.Elements[len(item.Elements)-1]
+	assert.Equal(t, "code", last.Type)
+	assert.Equal(t, "  bar", last.Text)
+}
+
+func TestParseThematicBreakSpecExamples(t *testing.T) {
+	type block struct {
+		Type string
+		Text string
+	}
+	cases := []struct {
+		content  string
+		expected []block
+	}{
+		{"***\n---\n___", []block{{"hr", ""}, {"hr", ""}, {"hr", ""}}},
+		{"+++", []block{{"text", "+++"}}},
+		{"--\n**\n__", []block{{"text", "--\n**\n__"}}},
+		{"Foo\n***\nbar", []block{{"text", "Foo"}, {"hr", ""}, {"text", "bar"}}},
+		{"Foo\n---\nbar", []block{{"h2", "Foo"}}},
+		{"Foo\n  ===  ", []block{{"h1", "Foo"}}},
+		{"* Foo\n* * *\n* Bar", []block{{"unordered-list", ""}, {"hr", ""}, {"unordered-list", ""}}},
+		{"    ***", []block{{"code", "***"}}},
+	}
+
+	for _, c := range cases {
+		doc := Parse(c.content)
+
+		result := []block{}
+		for _, el := range doc.Elements {
+			result = append(result, block{el.Type, el.Text})
+		}
+		assert.Equal(t, c.expected, result, c.content)
+	}
+}
+
+func TestParseThematicBreakIsLeafSibling(t *testing.T) {
+	doc := Parse("# Title\n\nfoo\n\n---\n\nbar")
+
+	h1 := doc.Elements[0]
+	assert.Len(t, h1.Elements, 4)
+	assert.Equal(t, "hr", h1.Elements[2].Type)
+	assert.Empty(t, h1.Elements[2].Elements)
+	assert.Equal(t, h1, h1.Elements[2].Parent)
+	assert.Equal(t, "text", h1.Elements[3].Type)
+}
+
+func TestParseHeadingSpecExamples(t *testing.T) {
+	type block struct {
+		Type  string
+		Level int
+		Text  string
+	}
+	cases := []struct {
+		content  string
+		expected []block
+	}{
+		{"####### foo", []block{{"text", 0, "####### foo"}}},
+		{"#5 bolt\n\n#hashtag", []block{{"text", 0, "#5 bolt"}, {"text", 0, "#hashtag"}}},
+		{"# foo *bar* \\*baz\\*", []block{{"h1", 1, "foo *bar* \\*baz\\*"}}},
+		{" ### foo", []block{{"h3", 3, "foo"}}},
+		{"    # foo", []block{{"code", 0, "# foo"}}},
+		{"## foo ##\n  ###   bar    ###", []block{{"h2", 2, "foo"}}},
+		{"****\n## foo\n****", []block{{"hr", 0, ""}, {"h2", 2, "foo"}}},
+		{"Foo bar\n# baz\nBar foo", []block{{"text", 0, "Foo bar"}, {"h1", 1, "baz"}}},
+		{"## \n#\n### ###", []block{{"h2", 2, ""}, {"h1", 1, ""}}},
+		{"Foo *bar\nbaz*\n====", []block{{"h1", 1, "Foo *bar\nbaz*"}}},
+		{"> foo\n===", []block{{"blockquote", 0, ""}}},
+		{"- foo\n---", []block{{"unordered-list", 0, ""}, {"hr", 0, ""}}},
+	}
+
+	for _, c := range cases {
+		doc := Parse(c.content)
+
+		result := []block{}
+		for _, el := range doc.Elements {
+			result = append(result, block{el.Type, el.Level, el.Text})
+		}
+		assert.Equal(t, c.expected, result, c.content)
+	}
+}
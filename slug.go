@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// headingIDPattern matches explicit {#custom-id} at the end of heading text
+var headingIDPattern = regexp.MustCompile(`(?:^|[ \t]+)\{#([^\s{}]+)\}$`)
+
+// splitHeadingID separates explicit {#custom-id} at the end of heading text
+func splitHeadingID(text string) (string, string) {
+	m := headingIDPattern.FindStringSubmatchIndex(text)
+	if m == nil {
+		return text, ""
+	}
+	return strings.TrimSpace(text[:m[0]]), text[m[2]:m[3]]
+}
+
+// Slugify creates GitHub compatible anchor from heading text.
+// Text is lowercased, punctuation is removed and spaces become hyphens.
+func Slugify(text string) string {
+	output := strings.Builder{}
+	for _, c := range strings.ToLower(text) {
+		switch {
+		case c == ' ':
+			output.WriteRune('-')
+		case c == '-' || c == '_' || unicode.IsLetter(c) || unicode.IsNumber(c) || unicode.IsMark(c):
+			output.WriteRune(c)
+		}
+	}
+	return output.String()
+}
+
+// assignHeadingIDs sets unique id attribute on every heading under root.
+// Explicit ids are kept, with warning when used twice,
+// and generated slugs get -1, -2 suffixes on collision.
+func (p *parser) assignHeadingIDs(root *Element) {
+	headings := collectHeadings(root)
+
+	used := map[string]bool{}
+	for _, heading := range headings {
+		if id, ok := heading.Attributes["id"]; ok {
+			if used[id] {
+				p.warn(heading.Span.Start.Line, "duplicate heading id {#"+id+"}")
+			}
+			used[id] = true
+		}
+	}
+
+	for _, heading := range headings {
+		if _, ok := heading.Attributes["id"]; ok {
+			continue
+		}
+
+		id := uniqueID(Slugify(headingText(heading)), used)
+		used[id] = true
+
+		if heading.Attributes == nil {
+			heading.Attributes = map[string]string{}
+		}
+		heading.Attributes["id"] = id
+	}
+}
+
+// uniqueID returns slug, followed by -1, -2 and so on when it is already used
+func uniqueID(slug string, used map[string]bool) string {
+	id := slug
+	for n := 1; used[id]; n++ {
+		id = slug + "-" + strconv.Itoa(n)
+	}
+	return id
+}
+
+// collectHeadings returns headings under root in document order
+func collectHeadings(root *Element) []*Element {
+	headings := []*Element{}
+	Walk(root, func(el *Element, entering bool) WalkStatus {
+		if el.Inline {
+			return WalkSkipChildren
+		}
+		if entering && el.Kind() == KindHeading {
+			headings = append(headings, el)
+		}
+		return WalkContinue
+	})
+	return headings
+}
+
+// headingText returns plain text of heading inline content
+func headingText(heading *Element) string {
+	inlines := []*Element{}
+	for _, child := range heading.Elements {
+		if child.Inline {
+			inlines = append(inlines, child)
+		}
+	}
+	if len(inlines) == 0 {
+		return heading.Text
+	}
+	return plainText(inlines)
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Title":                     "title",
+		"Hello, World!":             "hello-world",
+		"snake_case and kebab-case": "snake_case-and-kebab-case",
+		"Version 1.2.3":             "version-123",
+		"  two  spaces":             "--two--spaces",
+		"Ünïcode Überschrift":       "ünïcode-überschrift",
+		"?!":                        "",
+	}
+
+	for text, expected := range cases {
+		assert.Equal(t, expected, Slugify(text), text)
+	}
+}
+
+func TestSplitHeadingID(t *testing.T) {
+	text, id := splitHeadingID("Title {#custom-id}")
+
+	assert.Equal(t, "Title", text)
+	assert.Equal(t, "custom-id", id)
+}
+
+func TestSplitHeadingIDWithoutID(t *testing.T) {
+	for _, content := range []string{"Title", "Title{#id}", "Title {#id} more", "Title {#}"} {
+		text, id := splitHeadingID(content)
+
+		assert.Equal(t, content, text)
+		assert.Equal(t, "", id)
+	}
+}
+
+func TestParseHeadingIDs(t *testing.T) {
+	content := "# Intro\n\n## Intro\n\n## *Intro*\n\n### `code` & Links [here](/url)\n\n> # Quoted"
+
+	doc := Parse(content)
+
+	ids := []string{}
+	for _, heading := range collectHeadings(doc.Element) {
+		ids = append(ids, heading.Attributes["id"])
+	}
+	assert.Equal(t, []string{"intro", "intro-1", "intro-2", "code--links-here", "quoted"}, ids)
+}
+
+func TestParseExplicitHeadingID(t *testing.T) {
+	content := "# Intro {#start}\n\n## Start\n\nSetext {#custom}\n---"
+
+	doc := Parse(content)
+
+	h1 := doc.Elements[0]
+	assert.Equal(t, "Intro", h1.Text)
+	assert.Equal(t, "start", h1.Attributes["id"])
+	assert.Equal(t, "start-1", h1.Elements[1].Attributes["id"])
+	assert.Equal(t, "Setext", h1.Elements[2].Text)
+	assert.Equal(t, "custom", h1.Elements[2].Attributes["id"])
+	assert.Equal(t, "true", h1.Attributes["explicit-id"])
+	assert.Equal(t, "", h1.Elements[1].Attributes["explicit-id"])
+	assert.Empty(t, doc.Diagnostics)
+}
+
+func TestParseDuplicateExplicitHeadingID(t *testing.T) {
+	doc := Parse("# A {#x}\n\n# B {#x}\n\n# x")
+
+	assert.Equal(t, "x", doc.Elements[0].HeadingID())
+	assert.Equal(t, "x", doc.Elements[1].HeadingID())
+	assert.Equal(t, "x-1", doc.Elements[2].HeadingID())
+	assert.Equal(t, []Diagnostic{{Line: 3, Message: "duplicate heading id {#x}"}}, doc.Diagnostics)
+}
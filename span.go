@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a location in markdown source.
+// Line and Column start at 1, Column and Offset count bytes.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+}
+
+// Span is a range of markdown source from Start up to, not including, End
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// String formats position as line:column
+func (pos Position) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// IsZero checks whether position is unknown, as for elements not created by Parse
+func (pos Position) IsZero() bool {
+	return pos.Line == 0
+}
+
+// advance moves known position n bytes forward on the same line
+func (pos Position) advance(n int) Position {
+	if pos.IsZero() {
+		return pos
+	}
+	pos.Column += n
+	pos.Offset += n
+	return pos
+}
+
+// loadSource remembers source lines so that element spans can be located
+func (p *parser) loadSource(content string) {
+	p.lines = strings.Split(content, "\n")
+	p.offsets = make([]int, len(p.lines))
+	offset := 0
+	for i, line := range p.lines {
+		p.offsets[i] = offset
+		offset += len(line) + 1
+		// CRLF line ending is read as LF, offsets still count it
+		p.lines[i] = strings.TrimSuffix(line, "\r")
+	}
+}
+
+// locate returns source position of fragment of line, searching from column from.
+// Position at column from is returned when fragment is not found.
+func (p *parser) locate(line int, fragment string, from int) Position {
+	if line < 1 || line > len(p.lines) {
+		return Position{}
+	}
+
+	src := p.lines[line-1]
+	if from < 1 || from > len(src)+1 {
+		from = 1
+	}
+	column := from
+	if i := strings.Index(src[from-1:], fragment); i >= 0 {
+		column = from + i
+	}
+
+	return Position{
+		Line:   line,
+		Column: column,
+		Offset: p.offsets[line-1] + column - 1,
+	}
+}
+
+// locateSuffix returns source position of content left after stripping block
+// markers, such as > or list item indentation, from the beginning of line
+func (p *parser) locateSuffix(line int, content string) Position {
+	if line < 1 || line > len(p.lines) {
+		return Position{}
+	}
+
+	src := p.lines[line-1]
+	if !strings.HasSuffix(src, content) {
+		return p.locate(line, content, 1)
+	}
+
+	column := len(src) - len(content) + 1
+	return Position{
+		Line:   line,
+		Column: column,
+		Offset: p.offsets[line-1] + column - 1,
+	}
+}
+
+// sourcePosition translates position in content starting at line to position in source
+func (p *parser) sourcePosition(pos Position, lines []string, line int) Position {
+	return p.locateSuffix(line+pos.Line-1, lines[pos.Line-1]).advance(pos.Column - 1)
+}
+
+// lineSpan returns span of content lines starting at line
+func (p *parser) lineSpan(line int, content string) Span {
+	lines := strings.Split(content, "\n")
+	last := lines[len(lines)-1]
+	return Span{
+		Start: p.locateSuffix(line, lines[0]),
+		End:   p.locateSuffix(line+len(lines)-1, last).advance(len(last)),
+	}
+}
+
+// textLineStarts returns source position of each line of element text.
+// First line is searched from element start, the rest are expected at line ends.
+func (p *parser) textLineStarts(el *Element) []Position {
+	lines := strings.Split(el.Text, "\n")
+	starts := make([]Position, len(lines))
+	for k, text := range lines {
+		if k == 0 {
+			starts[k] = p.locate(el.Span.Start.Line, text, el.Span.Start.Column)
+		} else {
+			starts[k] = p.locateSuffix(el.Span.Start.Line+k, text)
+		}
+	}
+	return starts
+}
+
+// textPosition translates offset in text to source position using start of each text line
+func textPosition(text string, offset int, starts []Position) Position {
+	k := strings.Count(text[:offset], "\n")
+	lineOffset := strings.LastIndex(text[:offset], "\n") + 1
+	return starts[k].advance(offset - lineOffset)
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sourceOf returns part of content covered by element span
+func sourceOf(content string, el *Element) string {
+	return content[el.Span.Start.Offset:el.Span.End.Offset]
+}
+
+func TestParseBlockSpans(t *testing.T) {
+	content := "# Title #\n\nSome text\nmore text\n\n```go\ncode\n```\n\n***"
+
+	doc := Parse(content)
+
+	h1 := doc.Elements[0]
+	assert.Equal(t, Span{
+		Start: Position{Line: 1, Column: 1, Offset: 0},
+		End:   Position{Line: 1, Column: 10, Offset: 9},
+	}, h1.Span)
+	assert.Equal(t, Span{
+		Start: Position{Line: 3, Column: 1, Offset: 11},
+		End:   Position{Line: 4, Column: 10, Offset: 30},
+	}, h1.Elements[1].Span)
+	assert.Equal(t, "```go\ncode\n```", sourceOf(content, h1.Elements[2]))
+	assert.Equal(t, "***", sourceOf(content, h1.Elements[3]))
+}
+
+func TestParseNestedBlockSpans(t *testing.T) {
+	content := "> quote\n>\n> > nested\n\n- item\n\n  para\n  1. sub"
+
+	doc := Parse(content)
+
+	quote := doc.Elements[0]
+	assert.Equal(t, "> quote\n>\n> > nested", sourceOf(content, quote))
+	assert.Equal(t, "quote", sourceOf(content, quote.Elements[0]))
+	assert.Equal(t, Position{Line: 1, Column: 3, Offset: 2}, quote.Elements[0].Span.Start)
+
+	nested := quote.Elements[1]
+	assert.Equal(t, "> nested", sourceOf(content, nested))
+	assert.Equal(t, "nested", sourceOf(content, nested.Elements[0]))
+	assert.Equal(t, "nested", sourceOf(content, nested.Elements[0].Elements[0]))
+
+	list := doc.Elements[1]
+	item := list.Elements[0]
+	assert.Equal(t, "- item\n\n  para\n  1. sub", sourceOf(content, item))
+	assert.Equal(t, "item", sourceOf(content, item.Elements[0]))
+	assert.Equal(t, "para", sourceOf(content, item.Elements[1]))
+	sub := item.Elements[2].Elements[0]
+	assert.Equal(t, "1. sub", sourceOf(content, sub))
+	assert.Equal(t, Position{Line: 8, Column: 3, Offset: 39}, sub.Span.Start)
+}
+
+func TestParseTableSpans(t *testing.T) {
+	content := "| a | b |\n|---|---|\n| *x* | y |"
+
+	doc := Parse(content)
+
+	table := doc.Elements[0]
+	assert.Equal(t, content, sourceOf(content, table))
+	assert.Equal(t, "| a | b |", sourceOf(content, table.Elements[0]))
+	assert.Equal(t, "| *x* | y |", sourceOf(content, table.Elements[1]))
+	assert.Equal(t, "b", sourceOf(content, table.Elements[0].Elements[1]))
+	assert.Equal(t, "*x*", sourceOf(content, table.Elements[1].Elements[0]))
+	assert.Equal(t, "y", sourceOf(content, table.Elements[1].Elements[1]))
+}
+
+func TestParseTableSpansWithEscapedPipe(t *testing.T) {
+	content := "| a | b |\n|---|---|\n| e\\|f | **b** |\n> x | y\n> --|--\n> \\| | z"
+
+	doc := Parse(content)
+
+	row := doc.Elements[0].Elements[1]
+	assert.Equal(t, "e|f", row.Elements[0].Text)
+	assert.Equal(t, Span{
+		Start: Position{Line: 3, Column: 3, Offset: 22},
+		End:   Position{Line: 3, Column: 7, Offset: 26},
+	}, row.Elements[0].Span)
+	assert.Equal(t, "**b**", sourceOf(content, row.Elements[1]))
+
+	quoted := doc.Elements[1].Elements[0].Elements[1]
+	assert.Equal(t, "\\|", sourceOf(content, quoted.Elements[0]))
+	assert.Equal(t, "z", sourceOf(content, quoted.Elements[1]))
+}
+
+func TestParseInlineSpans(t *testing.T) {
+	content := "## Some *em* #\n\nA **bold** [link](/u)\nand `code`"
+
+	doc := Parse(content)
+
+	h2 := doc.Elements[0]
+	assert.Equal(t, "Some ", sourceOf(content, h2.Elements[0]))
+	assert.Equal(t, "*em*", sourceOf(content, h2.Elements[1]))
+	assert.Equal(t, "em", sourceOf(content, h2.Elements[1].Elements[0]))
+
+	paragraph := h2.Elements[2]
+	assert.Equal(t, "**bold**", sourceOf(content, paragraph.Elements[1]))
+	assert.Equal(t, "bold", sourceOf(content, paragraph.Elements[1].Elements[0]))
+	assert.Equal(t, "[link](/u)", sourceOf(content, paragraph.Elements[3]))
+	assert.Equal(t, "link", sourceOf(content, paragraph.Elements[3].Elements[0]))
+	assert.Equal(t, "`code`", sourceOf(content, paragraph.Elements[5]))
+	assert.Equal(t, Position{Line: 4, Column: 5, Offset: 42}, paragraph.Elements[5].Span.Start)
+}
+
+func TestConstructedElementHasNoSpan(t *testing.T) {
+	list := NewUnorderedList([]string{"item"})
+
+	assert.True(t, list.Span.Start.IsZero())
+	assert.True(t, list.Elements[0].Span.Start.IsZero())
+}
+
+func TestPositionString(t *testing.T) {
+	assert.Equal(t, "12:3", Position{Line: 12, Column: 3, Offset: 100}.String())
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Task is a task list item, - [ ] todo or - [x] done
+type Task struct {
+	// Index is position of task in document order, as used by ToggleTask
+	Index   int
+	Text    string
+	Checked bool
+	Item    *Element
+}
+
+// taskMarkerPattern matches [ ], [x] or [X] task marker followed by item text
+var taskMarkerPattern = regexp.MustCompile(`^\[([ xX])\][ \t]+(\S[\s\S]*)$`)
+
+// splitTaskMarker separates [ ], [x] or [X] task marker from the beginning of item text
+func splitTaskMarker(text string) (bool, string, bool) {
+	m := taskMarkerPattern.FindStringSubmatch(text)
+	if m == nil {
+		return false, text, false
+	}
+	return m[1] != " ", m[2], true
+}
+
+// Tasks returns task list items of document in document order
+func (d *Document) Tasks() []*Task {
+	tasks := []*Task{}
+	Walk(d.Element, func(el *Element, entering bool) WalkStatus {
+		if el.Inline {
+			return WalkSkipChildren
+		}
+		if entering && el.ListTask() {
+			tasks = append(tasks, &Task{
+				Index:   len(tasks),
+				Text:    headingText(el),
+				Checked: el.TaskChecked(),
+				Item:    el,
+			})
+		}
+		return WalkContinue
+	})
+	return tasks
+}
+
+// ToggleTask checks unchecked task at index of Tasks, or unchecks checked one.
+// RenderMarkdown writes the new state back.
+func (d *Document) ToggleTask(index int) error {
+	tasks := d.Tasks()
+	if index < 0 || index >= len(tasks) {
+		return fmt.Errorf("task %d out of range, document has %d tasks", index, len(tasks))
+	}
+
+	item := tasks[index].Item
+	item.Attributes["checked"] = strconv.FormatBool(!item.TaskChecked())
+	return nil
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTaskMarker(t *testing.T) {
+	tests := []struct {
+		text    string
+		checked bool
+		rest    string
+		ok      bool
+	}{
+		{"[ ] todo", false, "todo", true},
+		{"[x] done", true, "done", true},
+		{"[X]\tdone\nmore", true, "done\nmore", true},
+		{"[x]done", false, "[x]done", false},
+		{"[ ]", false, "[ ]", false},
+		{"[-] other", false, "[-] other", false},
+		{"text [x] later", false, "text [x] later", false},
+	}
+
+	for _, test := range tests {
+		checked, rest, ok := splitTaskMarker(test.text)
+
+		assert.Equal(t, test.checked, checked, test.text)
+		assert.Equal(t, test.rest, rest, test.text)
+		assert.Equal(t, test.ok, ok, test.text)
+	}
+}
+
+func TestListItemWithTaskMarker(t *testing.T) {
+	result := NewListItem("[x] done")
+
+	assert.Equal(t, "done", result.Text)
+	assert.Equal(t, map[string]string{"checked": "true"}, result.Attributes)
+	assert.True(t, result.ListTask())
+	assert.True(t, result.TaskChecked())
+}
+
+func TestOrderedListItemWithTaskMarker(t *testing.T) {
+	result := Parse("3. [ ] todo")
+
+	item := result.Elements[0].Elements[0]
+	assert.Equal(t, "todo", item.Text)
+	assert.Equal(t, map[string]string{"checked": "false", "number": "3"}, item.Attributes)
+}
+
+func TestDocumentTasks(t *testing.T) {
+	doc := Parse("- [ ] todo *now*\n- [x] done\n  - [X] nested\n- plain\n\n> - [ ] quoted")
+
+	tasks := doc.Tasks()
+
+	assert.Equal(t, 4, len(tasks))
+	assert.Equal(t, []string{"todo now", "done", "nested", "quoted"}, []string{tasks[0].Text, tasks[1].Text, tasks[2].Text, tasks[3].Text})
+	assert.Equal(t, []bool{false, true, true, false}, []bool{tasks[0].Checked, tasks[1].Checked, tasks[2].Checked, tasks[3].Checked})
+	assert.Equal(t, 2, tasks[2].Index)
+	assert.Equal(t, 3, tasks[2].Item.Span.Start.Line)
+}
+
+func TestToggleTask(t *testing.T) {
+	doc := Parse("- [ ] todo\n- [x] done\n  - [x] nested")
+
+	assert.NoError(t, doc.ToggleTask(0))
+	assert.NoError(t, doc.ToggleTask(2))
+
+	var out bytes.Buffer
+	assert.NoError(t, RenderMarkdown(doc, &out))
+	assert.Equal(t, "- [x] todo\n- [x] done\n  - [ ] nested\n", out.String())
+}
+
+func TestToggleTaskOutOfRange(t *testing.T) {
+	doc := Parse("- [ ] todo")
+
+	assert.EqualError(t, doc.ToggleTask(1), "task 1 out of range, document has 1 tasks")
+	assert.Error(t, doc.ToggleTask(-1))
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// tocEscaper escapes heading title for markdown link text
+var tocEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"[", "\\[",
+	"]", "\\]",
+)
+
+// TOCEntry is a heading in table of contents
+type TOCEntry struct {
+	Title    string
+	Level    int
+	Anchor   string
+	Children []*TOCEntry
+}
+
+// TOC builds table of contents from headings of level minLevel to maxLevel.
+// Each entry is nested under the closest preceding entry of lower level.
+func (d *Document) TOC(minLevel, maxLevel int) []*TOCEntry {
+	toc := []*TOCEntry{}
+	stack := []*TOCEntry{}
+
+	for _, heading := range collectHeadings(d.Element) {
+		level := heading.HeadingLevel()
+		if level < minLevel || level > maxLevel {
+			continue
+		}
+
+		entry := &TOCEntry{
+			Title:    headingText(heading),
+			Level:    level,
+			Anchor:   heading.Attributes["id"],
+			Children: []*TOCEntry{},
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc = append(toc, entry)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, entry)
+		}
+		stack = append(stack, entry)
+	}
+
+	return toc
+}
+
+// tocPlaceholderPattern matches [[TOC]] or <!-- toc --> placeholder
+var tocPlaceholderPattern = regexp.MustCompile(`^ {0,3}(\[\[TOC\]\]|<!--\s*(?i:toc)\s*-->)[ \t]*$`)
+
+// isTOCPlaceholder checks whether block is [[TOC]] or <!-- toc --> placeholder
+func isTOCPlaceholder(block string) bool {
+	return tocPlaceholderPattern.MatchString(block)
+}
+
+// RenderTOCMarkdown renders table of contents as nested markdown list of links
+func RenderTOCMarkdown(toc []*TOCEntry, w io.Writer) error {
+	buf := bytes.Buffer{}
+	writeTOCMarkdown(&buf, toc, 0)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeTOCMarkdown(buf *bytes.Buffer, toc []*TOCEntry, depth int) {
+	for _, entry := range toc {
+		buf.WriteString(strings.Repeat("  ", depth) + "- [" + tocEscaper.Replace(entry.Title) + "](#" + entry.Anchor + ")\n")
+		writeTOCMarkdown(buf, entry.Children, depth+1)
+	}
+}
+
+// RenderTOCHTML renders table of contents as nested HTML list of links
+func RenderTOCHTML(toc []*TOCEntry, w io.Writer) error {
+	buf := bytes.Buffer{}
+	writeTOCHTML(&buf, toc)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeTOCHTML(buf *bytes.Buffer, toc []*TOCEntry) {
+	if len(toc) == 0 {
+		return
+	}
+
+	buf.WriteString("<ul>\n")
+	for _, entry := range toc {
+		buf.WriteString("<li><a href=\"#" + escapeHTML(entry.Anchor) + "\">" + escapeHTML(entry.Title) + "</a>")
+		if len(entry.Children) > 0 {
+			buf.WriteString("\n")
+			writeTOCHTML(buf, entry.Children)
+		}
+		buf.WriteString("</li>\n")
+	}
+	buf.WriteString("</ul>\n")
+}
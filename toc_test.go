@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentTOC(t *testing.T) {
+	doc := Parse("# Guide\n\n## Install\n\n### From source\n\n## Usage\n\n# Reference")
+
+	toc := doc.TOC(1, 6)
+
+	assert.Equal(t, []*TOCEntry{
+		&TOCEntry{
+			Title:  "Guide",
+			Level:  1,
+			Anchor: "guide",
+			Children: []*TOCEntry{
+				&TOCEntry{
+					Title:  "Install",
+					Level:  2,
+					Anchor: "install",
+					Children: []*TOCEntry{
+						&TOCEntry{Title: "From source", Level: 3, Anchor: "from-source", Children: []*TOCEntry{}},
+					},
+				},
+				&TOCEntry{Title: "Usage", Level: 2, Anchor: "usage", Children: []*TOCEntry{}},
+			},
+		},
+		&TOCEntry{Title: "Reference", Level: 1, Anchor: "reference", Children: []*TOCEntry{}},
+	}, toc)
+}
+
+func TestDocumentTOCWithLevelRange(t *testing.T) {
+	doc := Parse("# Title\n\n## One\n\n### Deep\n\n## Two *em*")
+
+	toc := doc.TOC(2, 2)
+
+	assert.Equal(t, []*TOCEntry{
+		&TOCEntry{Title: "One", Level: 2, Anchor: "one", Children: []*TOCEntry{}},
+		&TOCEntry{Title: "Two em", Level: 2, Anchor: "two-em", Children: []*TOCEntry{}},
+	}, toc)
+}
+
+func TestDocumentTOCSkippedLevel(t *testing.T) {
+	doc := Parse("### Deep\n\n## Shallow")
+
+	toc := doc.TOC(1, 6)
+
+	assert.Len(t, toc, 2)
+	assert.Equal(t, "Deep", toc[0].Title)
+	assert.Equal(t, "Shallow", toc[1].Title)
+}
+
+func TestRenderTOCMarkdown(t *testing.T) {
+	doc := Parse("# Guide\n\n## Install [beta]\n\n## Usage")
+
+	var out bytes.Buffer
+	err := RenderTOCMarkdown(doc.TOC(1, 6), &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "- [Guide](#guide)\n  - [Install \\[beta\\]](#install-beta)\n  - [Usage](#usage)\n", out.String())
+}
+
+func TestRenderTOCHTML(t *testing.T) {
+	doc := Parse("# Fish & Chips\n\n## Usage")
+
+	var out bytes.Buffer
+	err := RenderTOCHTML(doc.TOC(1, 6), &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<ul>\n<li><a href=\"#fish--chips\">Fish &amp; Chips</a>\n<ul>\n<li><a href=\"#usage\">Usage</a></li>\n</ul>\n</li>\n</ul>\n", out.String())
+}
+
+func TestIsTOCPlaceholder(t *testing.T) {
+	for _, block := range []string{"[[TOC]]", "<!-- toc -->", "<!--TOC-->", "  [[TOC]]  "} {
+		assert.True(t, isTOCPlaceholder(block), block)
+	}
+	for _, block := range []string{"[[toc]]", "[[TOC]] here", "<!-- toc", "    [[TOC]]"} {
+		assert.False(t, isTOCPlaceholder(block), block)
+	}
+}
+
+func TestParseTOCPlaceholder(t *testing.T) {
+	doc := Parse("# Title\n\n[[TOC]]\n\n## Section")
+
+	toc := doc.Elements[0].Elements[1]
+	assert.Equal(t, "toc", toc.Type)
+	assert.Equal(t, "[[TOC]]", toc.Text)
+	assert.Empty(t, toc.Elements)
+}
+
+func TestRenderHTMLWithTOCLevels(t *testing.T) {
+	var out bytes.Buffer
+	err := RenderHTML(Parse("<!-- toc -->\n\n# Title\n\n## Section"), &out, HTMLTOCLevels(2, 6))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<ul>\n<li><a href=\"#section\">Section</a></li>\n</ul>\n<h1 id=\"title\">Title</h1>\n<h2 id=\"section\">Section</h2>\n", out.String())
+}
@@ -5,16 +5,27 @@ import (
 	"strings"
 )
 
-// linesBlock defines multilines block open/close pattern
-var linesBlock = map[string]string{
-	"^```.*$": "^```$",
-	"^~~~.*$": "^~~~$",
+// Token is a markdown block with its location in content
+type Token struct {
+	Text string
+	Line int
+	Span Span
 }
 
 // Tokenizer is markdown block tokenizer
 type Tokenizer struct {
-	Output []string
-	Block  []string
+	Output       []string
+	Tokens       []Token
+	Diagnostics  []Diagnostic
+	Block        []string
+	blockLine    int
+	lines        []string
+	offsets      []int
+	list         *listMarker
+	fence        *codeFence
+	indentedCode bool
+	footnote     bool
+	htmlBlock    int
 }
 
 // NewTokenizer creates a new tokenizer
@@ -25,28 +36,119 @@ func NewTokenizer() *Tokenizer {
 // Tokenize creates tokens from markdown content
 func (t *Tokenizer) Tokenize(content string) []string {
 	t.Output = []string{}
+	t.Tokens = []Token{}
+	t.Diagnostics = nil
 	t.Block = []string{}
-	blockType := ""
+	t.list = nil
+	t.fence = nil
+	t.indentedCode = false
+	t.footnote = false
+	t.htmlBlock = 0
+	fenceLine := 0
 
 	lines := strings.Split(content, "\n")
+	if n := len(lines); n > 1 && lines[n-1] == "" {
+		// line break ending content does not start another line
+		lines = lines[:n-1]
+	}
+	t.lines = lines
+	t.offsets = make([]int, len(lines))
+	for i, offset := 0, 0; i < len(lines); i++ {
+		t.offsets[i] = offset
+		offset += len(lines[i]) + 1
+		// CRLF line ending is read as LF, offsets still count it
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
 
-	for _, line := range lines {
-		if blockType != "" {
+	for i, line := range lines {
+		if t.indentedCode && strings.TrimSpace(line) != "" && indentation(line) < 4 {
+			t.flushBlock()
+		}
+
+		if t.fence != nil {
 			t.Block = append(t.Block, line)
-			if t.isEndOfLinesBlock(line, blockType) {
-				blockType = ""
+			if t.fence.isClosedBy(line) {
+				t.flushBlock()
 			}
-		} else {
-			if line == "" {
+		} else if t.htmlBlock != 0 && (t.htmlBlock < 6 || strings.TrimSpace(line) != "") {
+			// raw HTML runs to its end condition, kinds 6 and 7 to blank line
+			t.Block = append(t.Block, line)
+			if isHTMLBlockEnd(t.htmlBlock, line) {
 				t.flushBlock()
-			} else {
+			}
+		} else if strings.TrimSpace(line) == "" {
+			if t.list != nil && t.isListContinued(lines[i+1:]) {
+				t.Block = append(t.Block, "")
+			} else if t.indentedCode && isIndentedCodeContinued(lines[i+1:]) {
 				t.Block = append(t.Block, line)
-				if detectedType, ok := t.isBeginOfLinesBlock(line); ok {
-					blockType = detectedType
-				}
+			} else if t.footnote && isIndentedCodeContinued(lines[i+1:]) {
+				// indented paragraphs continue footnote definition
+				t.Block = append(t.Block, "")
+			} else {
+				t.flushBlock()
 			}
+		} else if t.indentedCode || (t.footnote && indentation(line) >= 4) {
+			t.Block = append(t.Block, line)
+		} else if len(t.Block) == 0 && indentation(line) >= 4 {
+			// indented code cannot interrupt a paragraph
+			t.blockLine = i + 1
+			t.Block = append(t.Block, line)
+			t.indentedCode = true
+		} else if fence, ok := parseCodeFence(line); ok && !t.isListContent(line) {
+			// code fence interrupts any block
+			t.flushBlock()
+			t.blockLine = i + 1
+			t.Block = append(t.Block, line)
+			t.fence = &fence
+			fenceLine = i + 1
+		} else if kind := htmlBlockStart(line); kind != 0 && !t.isListContent(line) && (kind < 7 || len(t.Block) == 0) {
+			// raw HTML interrupts any block except kind 7 which cannot interrupt a paragraph
+			t.flushBlock()
+			t.blockLine = i + 1
+			t.Block = append(t.Block, line)
+			t.htmlBlock = kind
+			if isHTMLBlockEnd(kind, line) {
+				t.flushBlock()
+			}
+		} else if isSetextUnderline(line) && t.isParagraph() {
+			// setext underline ends paragraph as heading
+			t.Block = append(t.Block, line)
+			t.flushBlock()
+		} else if isSingleLineBlock(line) && !t.isListContent(line) {
+			// thematic break and ATX heading interrupt any block.
+			// Thematic break takes precedence over list item.
+			t.flushBlock()
+			t.blockLine = i + 1
+			t.Block = append(t.Block, line)
+			t.flushBlock()
+		} else if blockquotePattern.MatchString(line) && !t.isListContent(line) {
+			// blockquote interrupts a paragraph and a list
+			if !t.isBlockquote() {
+				t.flushBlock()
+				t.blockLine = i + 1
+			}
+			t.Block = append(t.Block, line)
+		} else if isFootnoteDefinitionStart(line) && !t.isListContent(line) {
+			// footnote definition starts a new block
+			t.flushBlock()
+			t.blockLine = i + 1
+			t.Block = append(t.Block, line)
+			t.footnote = true
+		} else {
+			t.detectListItem(line)
+			if len(t.Block) == 0 {
+				t.blockLine = i + 1
+			}
+			t.Block = append(t.Block, line)
 		}
 	}
+	if t.fence != nil {
+		// unterminated fence runs to end of content
+		t.Diagnostics = append(t.Diagnostics, Diagnostic{
+			Line:    fenceLine,
+			Message: "unterminated code fence",
+		})
+	}
 	t.flushBlock()
 
 	return t.Output
@@ -55,25 +157,165 @@ func (t *Tokenizer) Tokenize(content string) []string {
 // flushBlock flushes content remained in block to output
 func (t *Tokenizer) flushBlock() {
 	if len(t.Block) > 0 {
-		t.Output = append(t.Output, strings.Join(t.Block, "\n"))
+		text := strings.Join(t.Block, "\n")
+		endLine := t.blockLine + len(t.Block) - 1
+		t.Output = append(t.Output, text)
+		t.Tokens = append(t.Tokens, Token{
+			Text: text,
+			Line: t.blockLine,
+			Span: Span{
+				Start: Position{Line: t.blockLine, Column: 1, Offset: t.offsets[t.blockLine-1]},
+				End:   Position{Line: endLine, Column: len(t.lines[endLine-1]) + 1, Offset: t.offsets[endLine-1] + len(t.lines[endLine-1])},
+			},
+		})
 		t.Block = []string{}
 	}
+	t.list = nil
+	t.fence = nil
+	t.indentedCode = false
+	t.footnote = false
+	t.htmlBlock = 0
 }
 
-func (t *Tokenizer) isBeginOfLinesBlock(line string) (string, bool) {
-	for pattern := range linesBlock {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(line) {
-			return pattern, true
+// detectListItem tracks list item marker of current list block.
+// A list item interrupts a paragraph and a change of list marker starts a new block.
+func (t *Tokenizer) detectListItem(line string) {
+	marker, ok := parseListMarker(line)
+	if !ok {
+		return
+	}
+
+	if t.list == nil {
+		if len(t.Block) > 0 {
+			if marker.content == "" || (marker.ordered && marker.number != "1") {
+				// item cannot interrupt a paragraph
+				return
+			}
+			t.flushBlock()
 		}
+		t.list = &marker
+		return
+	}
+
+	if marker.indent < t.list.contentIndent {
+		if marker.char != t.list.char {
+			t.flushBlock()
+		}
+		t.list = &marker
 	}
-	return "", false
 }
 
-func (t *Tokenizer) isEndOfLinesBlock(line, blockType string) bool {
-	if pattern, ok := linesBlock[blockType]; ok {
-		re := regexp.MustCompile(pattern)
-		return re.MatchString(line)
+// isListContinued checks whether list goes on after blank line
+func (t *Tokenizer) isListContinued(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if marker, ok := parseListMarker(line); ok && marker.char == t.list.char {
+			return true
+		}
+		return indentation(line) >= t.list.contentIndent
 	}
 	return false
 }
+
+// isIndentedCodeContinued checks whether indented code goes on after blank line
+func isIndentedCodeContinued(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return indentation(line) >= 4
+		}
+	}
+	return false
+}
+
+// isListContent checks whether line is indented as content of current list item
+func (t *Tokenizer) isListContent(line string) bool {
+	return t.list != nil && indentation(line) >= t.list.contentIndent
+}
+
+// isParagraph checks whether current block is a paragraph which may become setext heading
+func (t *Tokenizer) isParagraph() bool {
+	if len(t.Block) == 0 || t.list != nil || t.footnote {
+		return false
+	}
+	return !t.isBlockquote()
+}
+
+// isBlockquote checks whether current block is a blockquote
+func (t *Tokenizer) isBlockquote() bool {
+	return len(t.Block) > 0 && blockquotePattern.MatchString(t.Block[0])
+}
+
+// setextUnderlinePattern matches line made of = or - only
+var setextUnderlinePattern = regexp.MustCompile("^ {0,3}(=+|-+)[ \t]*$")
+
+// thematicBreakPattern matches line made of 3 or more *, - or _ with optional spaces between
+var thematicBreakPattern = regexp.MustCompile("^ {0,3}((\\*[ \t]*){3,}|(-[ \t]*){3,}|(_[ \t]*){3,})$")
+
+// isSetextUnderline checks whether line is made of = or - only, with up to 3 spaces of indentation
+func isSetextUnderline(line string) bool {
+	return setextUnderlinePattern.MatchString(line)
+}
+
+// isThematicBreak checks whether line is made of 3 or more *, - or _ with optional spaces between
+func isThematicBreak(line string) bool {
+	return thematicBreakPattern.MatchString(line)
+}
+
+// isSingleLineBlock checks whether line is a thematic break or an ATX heading
+func isSingleLineBlock(line string) bool {
+	_, _, heading := tryATXHeading(line)
+	return heading || isThematicBreak(line)
+}
+
+// codeFence describes opening fence of fenced code block
+type codeFence struct {
+	char   byte
+	length int
+	indent int
+	info   string
+}
+
+// codeFencePattern matches opening code fence and its info string
+var codeFencePattern = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+
+// closingFencePattern matches closing code fence
+var closingFencePattern = regexp.MustCompile("^ {0,3}(`+|~+)[ \t]*$")
+
+// parseCodeFence detects opening code fence with up to 3 spaces of indentation
+func parseCodeFence(line string) (codeFence, bool) {
+	m := codeFencePattern.FindStringSubmatch(line)
+	if m == nil || (m[2][0] == '`' && strings.Contains(m[3], "`")) {
+		return codeFence{}, false
+	}
+
+	return codeFence{
+		char:   m[2][0],
+		length: len(m[2]),
+		indent: len(m[1]),
+		info:   m[3],
+	}, true
+}
+
+// isClosedBy checks whether line is closing fence made of the same character
+// and at least as long as opening fence
+func (f *codeFence) isClosedBy(line string) bool {
+	m := closingFencePattern.FindStringSubmatch(line)
+	return m != nil && m[1][0] == f.char && len(m[1]) >= f.length
+}
+
+// indentation counts leading columns of line with tab stops of 4
+func indentation(line string) int {
+	columns := 0
+	for _, c := range line {
+		if c == ' ' {
+			columns++
+		} else if c == '\t' {
+			columns += 4 - columns%4
+		} else {
+			break
+		}
+	}
+	return columns
+}
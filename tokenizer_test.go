@@ -135,3 +135,279 @@ func TestTokenizeTableData(t *testing.T) {
 
 	assert.Equal(t, expected, result)
 }
+
+func TestTokenizeListWithBlankLinesBetweenItems(t *testing.T) {
+	content := "* item 1\n\n* item 2\n\n  continues\n\nparagraph"
+	expected := []string{"* item 1\n\n* item 2\n\n  continues", "paragraph"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeListInterruptsParagraph(t *testing.T) {
+	content := "paragraph\n* item 1\n* item 2"
+	expected := []string{"paragraph", "* item 1\n* item 2"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeOrderedListNotStartingAtOneDoesNotInterruptParagraph(t *testing.T) {
+	content := "The year was\n1986. A great year"
+	expected := []string{"The year was\n1986. A great year"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeListKindChangeStartsNewBlock(t *testing.T) {
+	content := "* item 1\n1. item 2\n   * nested"
+	expected := []string{"* item 1", "1. item 2\n   * nested"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeTokensWithLines(t *testing.T) {
+	content := "test\n\n\ntest2\ntest3\n\ntest4"
+	expected := []Token{
+		Token{Text: "test", Line: 1, Span: Span{
+			Start: Position{Line: 1, Column: 1, Offset: 0},
+			End:   Position{Line: 1, Column: 5, Offset: 4},
+		}},
+		Token{Text: "test2\ntest3", Line: 4, Span: Span{
+			Start: Position{Line: 4, Column: 1, Offset: 7},
+			End:   Position{Line: 5, Column: 6, Offset: 18},
+		}},
+		Token{Text: "test4", Line: 7, Span: Span{
+			Start: Position{Line: 7, Column: 1, Offset: 20},
+			End:   Position{Line: 7, Column: 6, Offset: 25},
+		}},
+	}
+	tokenizer := NewTokenizer()
+
+	tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, tokenizer.Tokens)
+}
+
+func TestTokenizeListMarkerChangeStartsNewBlock(t *testing.T) {
+	content := "- item 1\n- item 2\n+ item 3\n\n1. item 4\n1) item 5"
+	expected := []string{"- item 1\n- item 2", "+ item 3", "1. item 4", "1) item 5"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeUnterminatedCodeBlock(t *testing.T) {
+	content := "test\n\n```\ncode\n\nmore code"
+	expected := []Diagnostic{
+		Diagnostic{Line: 3, Message: "unterminated code fence"},
+	}
+	tokenizer := NewTokenizer()
+
+	tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, tokenizer.Diagnostics)
+}
+
+func TestTokenizeUnterminatedCodeBlockEndingWithLineBreak(t *testing.T) {
+	content := "```\ncode\n"
+	expected := []string{"```\ncode"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+	assert.Equal(t, Span{
+		Start: Position{Line: 1, Column: 1, Offset: 0},
+		End:   Position{Line: 2, Column: 5, Offset: 8},
+	}, tokenizer.Tokens[0].Span)
+}
+
+func TestTokenizeCodeBlockInterruptsParagraph(t *testing.T) {
+	content := "foo\n```\nbar\n```\nbaz"
+	expected := []string{"foo", "```\nbar\n```", "baz"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeLongerFenceWrapsShorterFence(t *testing.T) {
+	content := "````md\n```\ninner\n```\n````\n\nafter"
+	expected := []string{"````md\n```\ninner\n```\n````", "after"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeIndentedFence(t *testing.T) {
+	content := "   ~~~\n\n   code\n   ~~~"
+	expected := []string{"   ~~~\n\n   code\n   ~~~"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeIndentedCodeBlock(t *testing.T) {
+	content := "para\n\n    code\n\n\n    more code\n\nafter"
+	expected := []string{"para", "    code\n\n\n    more code", "after"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeIndentedCodeEndsAtUnindentedLine(t *testing.T) {
+	content := "    code\nparagraph"
+	expected := []string{"    code", "paragraph"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeIndentedLineContinuesParagraph(t *testing.T) {
+	content := "paragraph\n    continued"
+	expected := []string{"paragraph\n    continued"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeIndentedLineContinuesListItem(t *testing.T) {
+	content := "- item\n\n    continued"
+	expected := []string{"- item\n\n    continued"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestIndentation(t *testing.T) {
+	assert.Equal(t, 0, indentation("foo"))
+	assert.Equal(t, 3, indentation("   foo"))
+	assert.Equal(t, 4, indentation("\tfoo"))
+	assert.Equal(t, 4, indentation("  \tfoo"))
+	assert.Equal(t, 8, indentation("    \tfoo"))
+}
+
+func TestTokenizeThematicBreakInterruptsParagraph(t *testing.T) {
+	content := "foo\n***\nbar"
+	expected := []string{"foo", "***", "bar"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeSetextUnderlineEndsParagraph(t *testing.T) {
+	content := "Foo\n---\nbar\n===\nbaz"
+	expected := []string{"Foo\n---", "bar\n===", "baz"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeThematicBreakEndsList(t *testing.T) {
+	content := "- foo\n- bar\n* * *\n- baz"
+	expected := []string{"- foo\n- bar", "* * *", "- baz"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestIsThematicBreak(t *testing.T) {
+	for _, line := range []string{"***", "---", "___", " - - -", "   **  * ** * ** * **", "-------     "} {
+		assert.True(t, isThematicBreak(line), line)
+	}
+	for _, line := range []string{"+++", "===", "--", "    ---", "_ _ _ a", "*-*"} {
+		assert.False(t, isThematicBreak(line), line)
+	}
+}
+
+func TestTokenizeATXHeadingInterruptsParagraph(t *testing.T) {
+	content := "foo\n## heading\nbar"
+	expected := []string{"foo", "## heading", "bar"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeHashWithoutSpaceContinuesParagraph(t *testing.T) {
+	content := "foo\n#hashtag"
+	expected := []string{"foo\n#hashtag"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeFootnoteDefinitions(t *testing.T) {
+	content := "text\n[^1]: first\nlazy\n\n    second paragraph\n[^2]: other\n\nafter\n\n    code"
+	expected := []string{"text", "[^1]: first\nlazy\n\n    second paragraph", "[^2]: other", "after", "    code"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeFootnoteDefinitionIsNotSetextHeading(t *testing.T) {
+	content := "[^1]: note\n---"
+	expected := []string{"[^1]: note", "---"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeHTMLBlocks(t *testing.T) {
+	content := "<pre>\n+ a\n\n* b\n</pre>\ntext\n<!-- comment\n1) a\n-->\n<div>\n*a*\n\n<span>\n- b"
+	expected := []string{"<pre>\n+ a\n\n* b\n</pre>", "text", "<!-- comment\n1) a\n-->", "<div>\n*a*", "<span>\n- b"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTokenizeHTMLTagCannotInterruptParagraph(t *testing.T) {
+	content := "text\n<span>\nmore"
+	expected := []string{"text\n<span>\nmore"}
+	tokenizer := NewTokenizer()
+
+	result := tokenizer.Tokenize(content)
+
+	assert.Equal(t, expected, result)
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlErrorPattern matches line number and message of TOML error
+var tomlErrorPattern = regexp.MustCompile(`^toml: line (\d+)(?: \(last key "(?:[^"\\]|\\.)*"\))?: ((?s).*)$`)
+
+// parseTOML parses TOML front matter starting at line of source.
+// Front matter that cannot be decoded is reported and left out of data.
+func (p *parser) parseTOML(raw string, line int) map[string]interface{} {
+	data := map[string]interface{}{}
+	if _, err := toml.Decode(raw, &data); err != nil {
+		message := err.Error()
+		number := line
+		if m := tomlErrorPattern.FindStringSubmatch(message); m != nil {
+			if n, _ := strconv.Atoi(m[1]); n > 0 {
+				number = line + n - 1
+			}
+			message = m[2]
+		}
+		p.warn(number, "invalid front matter: "+message)
+		return map[string]interface{}{}
+	}
+	return tomlValue(data).(map[string]interface{})
+}
+
+// tomlValue converts decoded TOML integers to int and arrays of tables to []interface{}
+// like YAML and JSON values
+func tomlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case []map[string]interface{}:
+		items := []interface{}{}
+		for _, item := range v {
+			items = append(items, tomlValue(item))
+		}
+		return items
+	case []interface{}:
+		for i := range v {
+			v[i] = tomlValue(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = tomlValue(v[k])
+		}
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTOML(t *testing.T) {
+	raw := `# comment
+title = "Hello # not comment" # comment
+literal = 'C:\path'
+count = 1_000
+hex = 0xff
+ratio = 2.5
+draft = true
+date = 1979-05-27T07:32:00Z
+tags = [
+  "one",
+  "two",
+]
+point = { x = 1, y = 2 }
+site.name = "docs"
+
+[params]
+"quoted key" = "value"
+
+[[menu]]
+name = "first"
+
+[[menu]]
+name = "second"`
+	p := newParser()
+
+	result := p.parseTOML(raw, 2)
+
+	assert.Equal(t, map[string]interface{}{
+		"title":   "Hello # not comment",
+		"literal": `C:\path`,
+		"count":   1000,
+		"hex":     255,
+		"ratio":   2.5,
+		"draft":   true,
+		"date":    time.Date(1979, time.May, 27, 7, 32, 0, 0, time.UTC),
+		"tags":    []interface{}{"one", "two"},
+		"point":   map[string]interface{}{"x": 1, "y": 2},
+		"site":    map[string]interface{}{"name": "docs"},
+		"params":  map[string]interface{}{"quoted key": "value"},
+		"menu": []interface{}{
+			map[string]interface{}{"name": "first"},
+			map[string]interface{}{"name": "second"},
+		},
+	}, result)
+	assert.Empty(t, p.diagnostics)
+}
+
+func TestParseTOMLInvalid(t *testing.T) {
+	for raw, diagnostic := range map[string]Diagnostic{
+		"a = 1\nb = bare":        {Line: 3, Message: "invalid front matter: expected value but found \"bare\" instead"},
+		"a = 1\nnot a pair":      {Line: 3, Message: "invalid front matter: expected '.' or '=', but got 'a' instead"},
+		"title = \"unterminated": {Line: 2, Message: "invalid front matter: unexpected EOF; expected '\"'"},
+	} {
+		p := newParser()
+
+		result := p.parseTOML(raw, 2)
+
+		assert.Equal(t, map[string]interface{}{}, result, raw)
+		assert.Equal(t, []Diagnostic{diagnostic}, p.diagnostics, raw)
+	}
+}
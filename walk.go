@@ -0,0 +1,182 @@
+package parser
+
+// WalkStatus tells Walk how to go on after visiting an element
+type WalkStatus int
+
+const (
+	// WalkContinue goes on with children and siblings of element
+	WalkContinue WalkStatus = iota
+	// WalkSkipChildren skips children of element entered
+	WalkSkipChildren
+	// WalkStop ends traversal
+	WalkStop
+)
+
+// Walk traverses element tree depth-first in document order.
+// fn is called with entering true before children of element and with entering false after them.
+// Returning WalkSkipChildren when entering skips children, WalkStop ends the whole traversal.
+func Walk(el *Element, fn func(el *Element, entering bool) WalkStatus) {
+	walk(el, fn)
+}
+
+// walk visits element and its children, reporting whether traversal was stopped
+func walk(el *Element, fn func(el *Element, entering bool) WalkStatus) bool {
+	status := fn(el, true)
+	if status == WalkStop {
+		return true
+	}
+
+	if status != WalkSkipChildren {
+		for _, child := range el.Elements {
+			if walk(child, fn) {
+				return true
+			}
+		}
+	}
+
+	return fn(el, false) == WalkStop
+}
+
+// Visitor has a hook for each element type, called when entering and leaving element
+type Visitor interface {
+	VisitDocument(el *Element, entering bool) WalkStatus
+	VisitHeading(el *Element, entering bool) WalkStatus
+	VisitParagraph(el *Element, entering bool) WalkStatus
+	VisitCode(el *Element, entering bool) WalkStatus
+	VisitTable(el *Element, entering bool) WalkStatus
+	VisitRow(el *Element, entering bool) WalkStatus
+	VisitCell(el *Element, entering bool) WalkStatus
+	VisitList(el *Element, entering bool) WalkStatus
+	VisitListItem(el *Element, entering bool) WalkStatus
+	VisitBlockquote(el *Element, entering bool) WalkStatus
+	VisitThematicBreak(el *Element, entering bool) WalkStatus
+	VisitTOC(el *Element, entering bool) WalkStatus
+	VisitText(el *Element, entering bool) WalkStatus
+	VisitEmphasis(el *Element, entering bool) WalkStatus
+	VisitStrong(el *Element, entering bool) WalkStatus
+	VisitCodeSpan(el *Element, entering bool) WalkStatus
+	VisitLink(el *Element, entering bool) WalkStatus
+	VisitImage(el *Element, entering bool) WalkStatus
+	VisitFootnote(el *Element, entering bool) WalkStatus
+	VisitFootnoteReference(el *Element, entering bool) WalkStatus
+	VisitHTML(el *Element, entering bool) WalkStatus
+}
+
+// BaseVisitor visits every element without doing anything.
+// Embed it to implement only the hooks needed.
+type BaseVisitor struct{}
+
+// VisitDocument visits document root
+func (BaseVisitor) VisitDocument(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitHeading visits h1 to h6
+func (BaseVisitor) VisitHeading(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitParagraph visits block text
+func (BaseVisitor) VisitParagraph(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitCode visits code block
+func (BaseVisitor) VisitCode(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitTable visits table
+func (BaseVisitor) VisitTable(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitRow visits table row
+func (BaseVisitor) VisitRow(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitCell visits table cell
+func (BaseVisitor) VisitCell(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitList visits ordered and unordered list
+func (BaseVisitor) VisitList(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitListItem visits list item
+func (BaseVisitor) VisitListItem(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitBlockquote visits blockquote
+func (BaseVisitor) VisitBlockquote(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitThematicBreak visits hr
+func (BaseVisitor) VisitThematicBreak(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitTOC visits table of contents placeholder
+func (BaseVisitor) VisitTOC(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitText visits inline text
+func (BaseVisitor) VisitText(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitEmphasis visits emphasis
+func (BaseVisitor) VisitEmphasis(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitStrong visits strong emphasis
+func (BaseVisitor) VisitStrong(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitCodeSpan visits code span
+func (BaseVisitor) VisitCodeSpan(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitLink visits link
+func (BaseVisitor) VisitLink(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitImage visits image
+func (BaseVisitor) VisitImage(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitFootnote visits footnote definition
+func (BaseVisitor) VisitFootnote(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitFootnoteReference visits footnote reference
+func (BaseVisitor) VisitFootnoteReference(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// VisitHTML visits raw HTML block
+func (BaseVisitor) VisitHTML(el *Element, entering bool) WalkStatus { return WalkContinue }
+
+// WalkVisitor traverses element tree calling hook of visitor for type of each element.
+// Elements of unknown type are traversed without calling any hook.
+func WalkVisitor(el *Element, v Visitor) {
+	Walk(el, func(el *Element, entering bool) WalkStatus {
+		switch el.Kind() {
+		case KindDocument:
+			return v.VisitDocument(el, entering)
+		case KindHeading:
+			return v.VisitHeading(el, entering)
+		case KindParagraph:
+			return v.VisitParagraph(el, entering)
+		case KindCode:
+			return v.VisitCode(el, entering)
+		case KindTable:
+			return v.VisitTable(el, entering)
+		case KindRow:
+			return v.VisitRow(el, entering)
+		case KindCell:
+			return v.VisitCell(el, entering)
+		case KindUnorderedList, KindOrderedList:
+			return v.VisitList(el, entering)
+		case KindListItem:
+			return v.VisitListItem(el, entering)
+		case KindBlockquote:
+			return v.VisitBlockquote(el, entering)
+		case KindThematicBreak:
+			return v.VisitThematicBreak(el, entering)
+		case KindTOC:
+			return v.VisitTOC(el, entering)
+		case KindText:
+			return v.VisitText(el, entering)
+		case KindEmphasis:
+			return v.VisitEmphasis(el, entering)
+		case KindStrong:
+			return v.VisitStrong(el, entering)
+		case KindCodeSpan:
+			return v.VisitCodeSpan(el, entering)
+		case KindLink:
+			return v.VisitLink(el, entering)
+		case KindImage:
+			return v.VisitImage(el, entering)
+		case KindFootnote:
+			return v.VisitFootnote(el, entering)
+		case KindFootnoteReference:
+			return v.VisitFootnoteReference(el, entering)
+		case KindHTML:
+			return v.VisitHTML(el, entering)
+		}
+		return WalkContinue
+	})
+}
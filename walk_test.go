@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkVisitsEnteringAndLeaving(t *testing.T) {
+	doc := Parse("# Title\n\nSome *text*")
+
+	visits := []string{}
+	Walk(doc.Element, func(el *Element, entering bool) WalkStatus {
+		if entering {
+			visits = append(visits, "+"+el.Type)
+		} else {
+			visits = append(visits, "-"+el.Type)
+		}
+		return WalkContinue
+	})
+
+	assert.Equal(t, []string{
+		"+doc",
+		"+h1", "+text", "-text",
+		"+text", "+text", "-text", "+emphasis", "+text", "-text", "-emphasis", "-text",
+		"-h1",
+		"-doc",
+	}, visits)
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	doc := Parse("Some *text*\n\n- item")
+
+	visits := []string{}
+	Walk(doc.Element, func(el *Element, entering bool) WalkStatus {
+		if entering {
+			visits = append(visits, el.Type)
+		}
+		if el.Type == "text" && !el.Inline {
+			return WalkSkipChildren
+		}
+		return WalkContinue
+	})
+
+	assert.Equal(t, []string{"doc", "text", "unordered-list", "list-item", "text"}, visits)
+}
+
+func TestWalkStop(t *testing.T) {
+	doc := Parse("first\n\nsecond\n\nthird")
+
+	visits := []string{}
+	Walk(doc.Element, func(el *Element, entering bool) WalkStatus {
+		if entering && !el.Inline {
+			visits = append(visits, el.Text)
+		}
+		if el.Text == "second" {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+
+	assert.Equal(t, []string{"", "first", "second"}, visits)
+}
+
+// linkCollector gathers href of every link
+type linkCollector struct {
+	BaseVisitor
+	hrefs []string
+}
+
+func (c *linkCollector) VisitLink(el *Element, entering bool) WalkStatus {
+	if entering {
+		c.hrefs = append(c.hrefs, el.Attributes["href"])
+	}
+	return WalkContinue
+}
+
+func (c *linkCollector) VisitCode(el *Element, entering bool) WalkStatus {
+	return WalkSkipChildren
+}
+
+func TestWalkVisitor(t *testing.T) {
+	doc := Parse("# [Home](/)\n\n- [one](/1)\n\n> [two](/2)\n\n| [three](/3) |\n|---|\n\n```\n[not](/link)\n```")
+	collector := &linkCollector{}
+
+	WalkVisitor(doc.Element, collector)
+
+	assert.Equal(t, []string{"/", "/1", "/2", "/3"}, collector.hrefs)
+}
+
+// typeRecorder records hook called for each element entered
+type typeRecorder struct {
+	BaseVisitor
+	hooks []string
+}
+
+func (r *typeRecorder) VisitParagraph(el *Element, entering bool) WalkStatus {
+	if entering {
+		r.hooks = append(r.hooks, "paragraph")
+	}
+	return WalkContinue
+}
+
+func (r *typeRecorder) VisitText(el *Element, entering bool) WalkStatus {
+	if entering {
+		r.hooks = append(r.hooks, "text")
+	}
+	return WalkContinue
+}
+
+func (r *typeRecorder) VisitList(el *Element, entering bool) WalkStatus {
+	if entering {
+		r.hooks = append(r.hooks, el.Type)
+	}
+	return WalkContinue
+}
+
+func TestWalkVisitorDistinguishesParagraphAndInlineText(t *testing.T) {
+	doc := Parse("para\n\n1. item")
+	recorder := &typeRecorder{}
+
+	WalkVisitor(doc.Element, recorder)
+
+	assert.Equal(t, []string{"paragraph", "text", "ordered-list", "text"}, recorder.hooks)
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// yamlErrorPattern matches line number reported in YAML error
+var yamlErrorPattern = regexp.MustCompile(`^line (\d+): `)
+
+// parseYAML parses YAML front matter starting at line of source.
+// Front matter that cannot be decoded is reported and left out of data.
+// It returns false when raw decodes to something other than a mapping,
+// so the block is not front matter at all.
+func (p *parser) parseYAML(raw string, line int) (map[string]interface{}, bool) {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		message := strings.TrimPrefix(err.Error(), "yaml: ")
+		number := line
+		if m := yamlErrorPattern.FindStringSubmatch(message); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			number = line + n - 1
+			message = message[len(m[0]):]
+		}
+		p.warn(number, "invalid front matter: "+message)
+		return map[string]interface{}{}, true
+	}
+
+	if value == nil {
+		return map[string]interface{}{}, true
+	}
+	data, ok := yamlValue(value).(map[string]interface{})
+	return data, ok
+}
+
+// yamlValue converts decoded YAML mappings to map[string]interface{}
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, item := range v {
+			m[fmt.Sprint(key)] = yamlValue(item)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = yamlValue(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYAML(t *testing.T) {
+	raw := `# comment
+title: "Quoted: \"title\""
+name: 'it''s'
+url: http://example.com # link
+draft: false
+ratio: 0.5
+empty:
+tags:
+- one
+- two
+nested:
+  inner:
+    deep: 1
+  list: [1, [2, 3], {a: b}]
+items:
+  - name: first
+    value: 1
+  - second
+literal: |
+  line one
+    indented
+folded: >-
+  line one
+  line two
+last: ~`
+	p := newParser()
+
+	result, ok := p.parseYAML(raw, 2)
+
+	assert.Equal(t, map[string]interface{}{
+		"title": `Quoted: "title"`,
+		"name":  "it's",
+		"url":   "http://example.com",
+		"draft": false,
+		"ratio": 0.5,
+		"empty": nil,
+		"tags":  []interface{}{"one", "two"},
+		"nested": map[string]interface{}{
+			"inner": map[string]interface{}{"deep": 1},
+			"list":  []interface{}{1, []interface{}{2, 3}, map[string]interface{}{"a": "b"}},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"name": "first", "value": 1},
+			"second",
+		},
+		"literal": "line one\n  indented\n",
+		"folded":  "line one line two",
+		"last":    nil,
+	}, result)
+	assert.True(t, ok)
+	assert.Empty(t, p.diagnostics)
+}
+
+func TestParseYAMLAnchorsAndMultiLineScalars(t *testing.T) {
+	p := newParser()
+
+	result, ok := p.parseYAML("base: &base\n  a: 1\ncopy: *base\ntitle: first\n  second line\ntag: !!str 10", 2)
+
+	assert.Equal(t, map[string]interface{}{
+		"base":  map[string]interface{}{"a": 1},
+		"copy":  map[string]interface{}{"a": 1},
+		"title": "first second line",
+		"tag":   "10",
+	}, result)
+	assert.True(t, ok)
+	assert.Empty(t, p.diagnostics)
+}
+
+func TestParseYAMLInvalid(t *testing.T) {
+	p := newParser()
+
+	result, ok := p.parseYAML("a: 1\n    b: 2\nc: 3", 2)
+
+	assert.Equal(t, map[string]interface{}{}, result)
+	assert.True(t, ok)
+	assert.Equal(t, []Diagnostic{
+		{Line: 3, Message: "invalid front matter: mapping values are not allowed in this context"},
+	}, p.diagnostics)
+}
+
+func TestParseYAMLUnterminatedQuote(t *testing.T) {
+	p := newParser()
+
+	result, ok := p.parseYAML("title: \"unterminated", 2)
+
+	assert.Equal(t, map[string]interface{}{}, result)
+	assert.True(t, ok)
+	assert.Equal(t, []Diagnostic{
+		{Line: 2, Message: "invalid front matter: found unexpected end of stream"},
+	}, p.diagnostics)
+}
+
+func TestParseYAMLNotMapping(t *testing.T) {
+	p := newParser()
+
+	result, ok := p.parseYAML("- a\n- b", 2)
+
+	assert.Nil(t, result)
+	assert.False(t, ok)
+	assert.Empty(t, p.diagnostics)
+}